@@ -9,18 +9,24 @@ import (
 	"github.com/abirhasanmubin/changelog-go/command"
 )
 
-type GitCommit struct {
-	Hash      string
-	Message   string
-	CommitUrl string
-}
-
 type Metadata struct {
-	Branch       string
-	TargetBranch string
-	UserName     string
-	CommitUrl    string
-	Commits      []GitCommit
+	Branch       string `json:"branch" yaml:"branch"`
+	TargetBranch string `json:"target_branch,omitempty" yaml:"target_branch,omitempty"`
+	UserName     string `json:"user_name,omitempty" yaml:"user_name,omitempty"`
+	CommitUrl    string `json:"commit_url,omitempty" yaml:"commit_url,omitempty"`
+	// CompareUrl and PullRequestUrl link to the branch's changes against
+	// the target branch on the repository's forge.
+	CompareUrl     string      `json:"compare_url,omitempty" yaml:"compare_url,omitempty"`
+	PullRequestUrl string      `json:"pull_request_url,omitempty" yaml:"pull_request_url,omitempty"`
+	Commits        []GitCommit `json:"commits,omitempty" yaml:"commits,omitempty"`
+	// Files are the files changed since the branch forked from the target
+	// branch.
+	Files []FileChange `json:"files,omitempty" yaml:"files,omitempty"`
+	// Tickets are the issue tracker keys found in the branch name and
+	// commits.
+	Tickets []Ticket `json:"tickets,omitempty" yaml:"tickets,omitempty"`
+	// Contributors are the authors and co-authors of the commits.
+	Contributors []Contributor `json:"contributors,omitempty" yaml:"contributors,omitempty"`
 }
 
 func (metadata Metadata) GenerateFilename() string {
@@ -31,28 +37,46 @@ func (metadata Metadata) GenerateFilename() string {
 	return fmt.Sprintf("%d_%s_%s.md", timestamp, safeUsername, safeBranch)
 }
 
-type Checklist struct {
-	SelfReview       bool
-	IncludesTesting  bool
-	Documentation    bool
-	EngineerReachout bool
-	ReadmeUpdated    bool
-}
-
 type Entry struct {
-	Title        string
-	Motivation   string
-	Description  string
-	Todos        []string
-	ModelChanges []string
-	Testing      []string
-	Filename     string
-	Checklist    Checklist
-	Metadata     Metadata
+	Title        string    `json:"title" yaml:"title"`
+	Motivation   string    `json:"motivation,omitempty" yaml:"motivation,omitempty"`
+	Description  string    `json:"description,omitempty" yaml:"description,omitempty"`
+	Todos        []string  `json:"todos,omitempty" yaml:"todos,omitempty"`
+	ModelChanges []string  `json:"model_changes,omitempty" yaml:"model_changes,omitempty"`
+	Testing      []string  `json:"testing,omitempty" yaml:"testing,omitempty"`
+	Filename     string    `json:"-" yaml:"-"`
+	Checklist    Checklist `json:"checklist" yaml:"checklist"`
+	Metadata     Metadata  `json:"metadata" yaml:"metadata"`
+	// Components are the monorepo components the change belongs to.
+	Components []string `json:"components,omitempty" yaml:"components,omitempty"`
+
+	// ChangeTypes are the options the entry was asked with. Nil means
+	// DefaultChangeTypes.
+	ChangeTypes []string `json:"change_types,omitempty" yaml:"change_types,omitempty"`
+	// Templates render the entry. Nil means DefaultTemplates.
+	Templates *Templates `json:"-" yaml:"-"`
+	// FrontMatterFormat is how SaveToFile writes the front matter, one of
+	// FrontMatterFormats. Empty means FrontMatterYAML.
+	FrontMatterFormat string `json:"-" yaml:"-"`
+	// FileList configures the Files changed section.
+	FileList FileListOptions `json:"-" yaml:"-"`
+	// TicketPatterns find the entry's tickets. Nil finds none.
+	TicketPatterns []TicketPattern `json:"-" yaml:"-"`
+	// ContributorList configures the Contributors line.
+	ContributorList ContributorOptions `json:"-" yaml:"-"`
+	// ExcludedPaths, relative to the repository root, are not part of the
+	// change, such as the entries themselves: commits that only touch them
+	// are left out of the commit list.
+	ExcludedPaths []string `json:"-" yaml:"-"`
+	// ComponentPaths define the components suggested from the changed
+	// files. Nil suggests none.
+	ComponentPaths []Component `json:"-" yaml:"-"`
+
+	touchedComponents []string
 }
 
 func (e *Entry) PopulateMetadata() {
-	cmd := command.Commands{Cmd: command.CommandRunner{}}
+	cmd := command.New()
 
 	branch, _ := cmd.GetCurrentBranch()
 	username, _ := cmd.GetUsername()
@@ -69,244 +93,98 @@ func (e *Entry) PopulateMetadata() {
 }
 
 func (e *Entry) PopulateCommitHistory(targetBranch string) {
-	cmd := command.Commands{Cmd: command.CommandRunner{}}
+	cmd := command.New()
 	e.Metadata.TargetBranch = targetBranch
+	if repo, err := cmd.GetRepository(); err == nil && targetBranch != "" {
+		e.Metadata.CompareUrl = repo.CompareURL(targetBranch, e.Metadata.Branch)
+		e.Metadata.PullRequestUrl = repo.PullRequestURL(targetBranch, e.Metadata.Branch)
+	}
+
+	log, _ := cmd.GetCommitLogBetweenBranches(targetBranch, e.Metadata.Branch, e.ExcludedPaths...)
+	e.Metadata.Commits = parseCommitLog(log, e.Metadata.CommitUrl)
+	e.Metadata.Tickets = ExtractTickets(e.TicketPatterns, e.Metadata.Branch, e.Metadata.Commits)
 
-	commitsStr, _ := cmd.GetCommitsBetweenBranches(targetBranch, e.Metadata.Branch)
+	e.Metadata.Contributors = nil
+	if !e.ContributorList.Skip {
+		e.Metadata.Contributors = CollectContributors(e.Metadata.Commits, cmd.CheckMailmap, e.ContributorList.Handles)
+	}
 
-	var commits []GitCommit
-	if commitsStr != "" {
-		lines := strings.Split(commitsStr, "\n")
-		for _, line := range lines {
-			if line != "" {
-				parts := strings.SplitN(line, " ", 2)
-				if len(parts) == 2 {
-					commits = append(commits, GitCommit{
-						Hash:      parts[0],
-						Message:   parts[1],
-						CommitUrl: e.Metadata.CommitUrl + parts[0],
-					})
-				}
-			}
+	e.Metadata.Files = nil
+	e.touchedComponents = nil
+	if !e.FileList.Skip || len(e.ComponentPaths) > 0 {
+		numstat, _ := cmd.GetDiffNumstat(targetBranch, e.Metadata.Branch)
+		files := parseNumstat(numstat)
+		e.touchedComponents = DetectComponents(e.ComponentPaths, files)
+		if !e.FileList.Skip {
+			e.Metadata.Files = files
 		}
 	}
-	e.Metadata.Commits = commits
 }
 
 func NewEntry() Entry {
-	entry := Entry{}
+	entry := Entry{Checklist: DefaultChecklist()}
 	entry.PopulateMetadata()
 
 	return entry
 }
 
-var changeTypes = []string{"Bug fix", "New feature", "Code refactor", "Breaking change", "Documentation update", "Other"}
-
-func (e *Entry) GenerateMarkdown(selectedTypes map[string]string) string {
-	var md strings.Builder
-
-	e.writeTitle(&md)
-	e.writeOptionalSection(&md, "Motivation", e.Motivation)
-	e.writeOptionalSection(&md, "Description", e.Description)
-	e.writeChangeTypes(&md, selectedTypes)
-	e.writeOptionalList(&md, "To-do before merge", e.Todos, "- [ ] %s\n")
-	e.writeOptionalList(&md, "Changes to existing models:", e.ModelChanges, "- %s\n")
-	e.writeTestingInstructions(&md)
-	e.writeChecklist(&md)
-	e.writeCommitList(&md)
-
-	return md.String()
-}
-
-func (e *Entry) writeTitle(md *strings.Builder) {
-	md.WriteString("## Title\n\n")
-	md.WriteString(e.Title + "\n\n")
-}
-
-func (e *Entry) writeOptionalSection(md *strings.Builder, title, content string) {
-	if strings.TrimSpace(content) == "" {
-		return
-	}
-	md.WriteString(fmt.Sprintf("## %s\n\n", title))
-	for _, line := range strings.Split(content, "\n") {
-		md.WriteString(line + "  \n")
-	}
-	md.WriteString("\n")
-}
-
-func (e *Entry) writeChangeTypes(md *strings.Builder, selectedTypes map[string]string) {
-	md.WriteString("## Type of change\n\n")
-	for _, changeType := range changeTypes {
-		if val, exists := selectedTypes[changeType]; exists && val != "" {
-			if changeType == "Other" && val != changeType {
-				md.WriteString(fmt.Sprintf("- [x] %s: %s\n", changeType, val))
-			} else {
-				md.WriteString(fmt.Sprintf("- [x] %s\n", changeType))
-			}
-		} else {
-			md.WriteString(fmt.Sprintf("- [ ] %s\n", changeType))
-		}
-	}
-	md.WriteString("\n")
-}
+// DefaultChangeTypes are the change types used when a repository does not
+// configure its own.
+var DefaultChangeTypes = []string{"Bug fix", "New feature", "Code refactor", "Breaking change", "Documentation update", "Other"}
 
-func (e *Entry) writeOptionalList(md *strings.Builder, title string, items []string, format string) {
-	if len(items) == 0 {
-		return
-	}
-	md.WriteString(fmt.Sprintf("## %s\n\n", title))
-	for _, item := range items {
-		md.WriteString(fmt.Sprintf(format, item))
+func (e *Entry) changeTypes() []string {
+	if e.ChangeTypes == nil {
+		return DefaultChangeTypes
 	}
-	md.WriteString("\n")
+	return e.ChangeTypes
 }
 
-func (e *Entry) writeTestingInstructions(md *strings.Builder) {
-	if len(e.Testing) == 0 {
-		return
+// checklist returns the entry's checklist, the unanswered default one when
+// it was never set.
+func (e *Entry) checklist() Checklist {
+	if e.Checklist == nil {
+		return DefaultChecklist()
 	}
-	md.WriteString("## Testing Instructions\n\n")
-	for i, step := range e.Testing {
-		md.WriteString(fmt.Sprintf("%d. %s\n", i+1, step))
-	}
-	md.WriteString("\n")
+	return e.Checklist
 }
 
-func (e *Entry) writeChecklist(md *strings.Builder) {
-	md.WriteString("## Checklist\n\n")
-	checklist := []struct {
-		text    string
-		checked bool
-	}{
-		{"I have performed a self-review of my code", e.Checklist.SelfReview},
-		{"I have added tests that prove my fix is effective or my feature works", e.Checklist.IncludesTesting},
-		{"I have added necessary documentation (if appropriate)", e.Checklist.Documentation},
-		{"I have proactively reached out to an engineer to review this PR", e.Checklist.EngineerReachout},
-		{"I have updated the README file (if appropriate)", e.Checklist.ReadmeUpdated},
-	}
-	for _, item := range checklist {
-		md.WriteString(fmt.Sprintf("- [%s] %s\n", checkboxValue(item.checked), item.text))
+func (e *Entry) templates() *Templates {
+	if e.Templates == nil {
+		return DefaultTemplates()
 	}
-	md.WriteString("\n")
+	return e.Templates
 }
 
-func (e *Entry) writeCommitList(md *strings.Builder) {
-	if len(e.Metadata.Commits) == 0 {
-		return
-	}
-	md.WriteString("## Commit List\n\n")
-	if e.Metadata.TargetBranch != "" {
-		md.WriteString(fmt.Sprintf("Commits from '%s' to '%s':\n", e.Metadata.TargetBranch, e.Metadata.Branch))
-	} else {
-		md.WriteString(fmt.Sprintf("Commits from branch '%s':\n", e.Metadata.Branch))
-	}
-	for _, commit := range e.Metadata.Commits {
-		md.WriteString(fmt.Sprintf("- [%s](%s) %s\n", commit.Hash[:7], commit.CommitUrl, commit.Message))
-	}
-	md.WriteString("\n")
+// Render executes the entry's named template.
+func (e *Entry) Render(name string, selectedTypes map[string]string) (string, error) {
+	return e.templates().Render(name, e, selectedTypes)
 }
 
-func checkboxValue(checked bool) string {
-	if checked {
-		return "x"
-	}
-	return " "
+// GenerateMarkdown renders the entry with its markdown template, or the
+// built-in one when a repository template fails. Render reports the error.
+func (e *Entry) GenerateMarkdown(selectedTypes map[string]string) string {
+	return e.renderOrBuiltin(TemplateMarkdown, selectedTypes)
 }
 
+// GenerateBitbucketPR renders the entry with its pull request template, or
+// the built-in one when a repository template fails.
 func (e *Entry) GenerateBitbucketPR(selectedTypes map[string]string) string {
-	var content strings.Builder
-
-	content.WriteString("### " + e.Title + "\n\n")
-
-	if strings.TrimSpace(e.Motivation) != "" {
-		content.WriteString("**Motivation:**\n" + e.Motivation + "\n\n")
-	}
-
-	if strings.TrimSpace(e.Description) != "" {
-		content.WriteString(e.Description + "\n\n")
-	}
-
-	e.writePRChangeTypes(&content, selectedTypes)
-	e.writePROptionalList(&content, "To-do before merge:", e.Todos, "- [ ] %s\n")
-	e.writePROptionalList(&content, "Changes to existing models:", e.ModelChanges, "- %s\n")
-	e.writePRTestingInstructions(&content)
-	e.writePRChecklist(&content)
-	e.writePRCommitList(&content)
-
-	return content.String()
-}
-
-func (e *Entry) writePRChangeTypes(content *strings.Builder, selectedTypes map[string]string) {
-	content.WriteString("**Type of change:**\n")
-	for _, changeType := range changeTypes {
-		if val, exists := selectedTypes[changeType]; exists && val != "" {
-			if changeType == "Other" && val != changeType {
-				content.WriteString(fmt.Sprintf("- ✅ %s: %s\n", changeType, val))
-			} else {
-				content.WriteString(fmt.Sprintf("- ✅ %s\n", changeType))
-			}
-		}
-	}
-	content.WriteString("\n")
-}
-
-func (e *Entry) writePROptionalList(content *strings.Builder, title string, items []string, format string) {
-	if len(items) == 0 {
-		return
-	}
-	content.WriteString(fmt.Sprintf("**%s**\n", title))
-	for _, item := range items {
-		content.WriteString(fmt.Sprintf(format, item))
-	}
-	content.WriteString("\n")
-}
-
-func (e *Entry) writePRTestingInstructions(content *strings.Builder) {
-	if len(e.Testing) == 0 {
-		return
-	}
-	content.WriteString("**Testing Instructions:**\n")
-	for i, step := range e.Testing {
-		content.WriteString(fmt.Sprintf("%d. %s\n", i+1, step))
-	}
-	content.WriteString("\n")
+	return e.renderOrBuiltin(TemplatePR, selectedTypes)
 }
 
-func (e *Entry) writePRCommitList(content *strings.Builder) {
-	if len(e.Metadata.Commits) == 0 {
-		return
+func (e *Entry) renderOrBuiltin(name string, selectedTypes map[string]string) string {
+	if content, err := e.Render(name, selectedTypes); err == nil {
+		return content
 	}
-	content.WriteString("**Commits:**\n")
-	for _, commit := range e.Metadata.Commits {
-		content.WriteString(fmt.Sprintf("- [%s](%s) %s\n", commit.Hash[:7], commit.CommitUrl, commit.Message))
-	}
-	content.WriteString("\n")
-}
-
-func (e *Entry) writePRChecklist(content *strings.Builder) {
-	content.WriteString("**Checklist:**\n")
-	checklist := []struct {
-		text    string
-		checked bool
-	}{
-		{"I have performed a self-review of my code", e.Checklist.SelfReview},
-		{"I have added tests that prove my fix is effective or my feature works", e.Checklist.IncludesTesting},
-		{"I have added necessary documentation (if appropriate)", e.Checklist.Documentation},
-		{"I have proactively reached out to an engineer to review this PR", e.Checklist.EngineerReachout},
-		{"I have updated the README file (if appropriate)", e.Checklist.ReadmeUpdated},
-	}
-	for _, item := range checklist {
-		icon := "❌"
-		if item.checked {
-			icon = "✅"
-		}
-		content.WriteString(fmt.Sprintf("- %s %s\n", icon, item.text))
-	}
-	content.WriteString("\n")
+	content, _ := DefaultTemplates().Render(name, e, selectedTypes)
+	return content
 }
 
 func (e *Entry) SaveToFile(selectedTypes map[string]string, filePath string) error {
-	content := e.GenerateMarkdown(selectedTypes)
+	content, err := e.GenerateFile(selectedTypes)
+	if err != nil {
+		return err
+	}
 
 	// Create directory if it doesn't exist
 	dir := strings.TrimSuffix(filePath, "/"+e.Filename)
@@ -3,6 +3,7 @@ package changelog
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -97,6 +98,9 @@ func TestNewEntry(t *testing.T) {
 	if entry.Filename == "" {
 		t.Error("expected filename to be populated")
 	}
+	if !entry.Checklist.Set(ChecklistSelfReview, true) {
+		t.Error("expected the default checklist to be answerable")
+	}
 }
 
 func TestEntry_GenerateMarkdown(t *testing.T) {
@@ -107,13 +111,7 @@ func TestEntry_GenerateMarkdown(t *testing.T) {
 		Todos:       []string{"Todo 1", "Todo 2"},
 		ModelChanges: []string{"Change 1", "Change 2"},
 		Testing:     []string{"Step 1", "Step 2"},
-		Checklist: Checklist{
-			SelfReview:       true,
-			IncludesTesting:  false,
-			Documentation:    true,
-			EngineerReachout: false,
-			ReadmeUpdated:    true,
-		},
+		Checklist:   checklistWith(ChecklistSelfReview, ChecklistDocumentation, ChecklistReadmeUpdated),
 		Metadata: Metadata{
 			Branch: "main",
 			Commits: []GitCommit{
@@ -328,30 +326,172 @@ func TestGitCommit(t *testing.T) {
 	}
 }
 
-func TestChecklist(t *testing.T) {
-	checklist := Checklist{
-		SelfReview:       true,
-		IncludesTesting:  false,
-		Documentation:    true,
-		EngineerReachout: false,
-		ReadmeUpdated:    true,
+func TestParseCommitLog(t *testing.T) {
+	log := "abc1234\x1fJane Doe\x1fjane@example.com\x1f2024-03-01T10:00:00+01:00\x1f2024-03-02T09:30:00Z\x1fp1 p2\x1fG\x1f" +
+		"feat: add login\x1fCo-authored-by: Sam <sam@example.com>\nRefs: #12, #13\n\x1f" +
+		"Longer explanation\n\nBREAKING CHANGE: drops v1\nCo-authored-by: Sam <sam@example.com>\nRefs: #12, #13\n\x1e\n" +
+		"def5678\x1fSam\x1fsam@example.com\x1f2024-03-03T08:00:00Z\x1f2024-03-03T08:00:00Z\x1fabc1234\x1fN\x1ffix(api): handle nil\x1f\x1f\x1e"
+
+	commits := parseCommitLog(log, "https://example.com/commit/")
+	expected := []GitCommit{
+		{
+			Hash:        "abc1234",
+			Message:     "feat: add login",
+			Body:        "Longer explanation\n\nBREAKING CHANGE: drops v1\nCo-authored-by: Sam <sam@example.com>\nRefs: #12, #13",
+			CommitUrl:   "https://example.com/commit/abc1234",
+			AuthorName:  "Jane Doe",
+			AuthorEmail: "jane@example.com",
+			AuthorDate:  time.Date(2024, 3, 1, 10, 0, 0, 0, time.FixedZone("", 3600)),
+			CommitDate:  time.Date(2024, 3, 2, 9, 30, 0, 0, time.UTC),
+			Trailers: []Trailer{
+				{Key: "Co-authored-by", Value: "Sam <sam@example.com>"},
+				{Key: "Refs", Value: "#12, #13"},
+			},
+			Parents:   2,
+			Signature: SignatureGood,
+		},
+		{
+			Hash:        "def5678",
+			Message:     "fix(api): handle nil",
+			CommitUrl:   "https://example.com/commit/def5678",
+			AuthorName:  "Sam",
+			AuthorEmail: "sam@example.com",
+			AuthorDate:  time.Date(2024, 3, 3, 8, 0, 0, 0, time.UTC),
+			CommitDate:  time.Date(2024, 3, 3, 8, 0, 0, 0, time.UTC),
+			Parents:     1,
+		},
 	}
+	if len(commits) != len(expected) {
+		t.Fatalf("expected %d commits, got %+v", len(expected), commits)
+	}
+	for i := range expected {
+		if !commits[i].AuthorDate.Equal(expected[i].AuthorDate) || !commits[i].CommitDate.Equal(expected[i].CommitDate) {
+			t.Errorf("commit %d: unexpected dates %v, %v", i, commits[i].AuthorDate, commits[i].CommitDate)
+		}
+		commits[i].AuthorDate, commits[i].CommitDate = expected[i].AuthorDate, expected[i].CommitDate
+	}
+	if !reflect.DeepEqual(commits, expected) {
+		t.Errorf("expected %+v, got %+v", expected, commits)
+	}
+	if commits := parseCommitLog("", ""); commits != nil {
+		t.Errorf("expected no commits, got %+v", commits)
+	}
+}
 
-	if !checklist.SelfReview {
+func TestGitCommit_Trailers(t *testing.T) {
+	commit := GitCommit{
+		Trailers: []Trailer{
+			{Key: "Signed-off-by", Value: "Jane <jane@example.com>"},
+			{Key: "co-authored-by", Value: "Sam <sam@example.com>"},
+			{Key: "Refs", Value: "#12, PROJ-7"},
+			{Key: "Refs", Value: "#40"},
+		},
+	}
+	if got := commit.CoAuthors(); !reflect.DeepEqual(got, []string{"Sam <sam@example.com>"}) {
+		t.Errorf("unexpected co-authors %q", got)
+	}
+	if got := commit.Refs(); !reflect.DeepEqual(got, []string{"#12", "PROJ-7", "#40"}) {
+		t.Errorf("unexpected refs %q", got)
+	}
+	if commit.Signed() || !(GitCommit{Signature: SignatureBad}).Signed() {
+		t.Error("expected only commits with a signature status to be signed")
+	}
+}
+
+// checklistWith returns the default checklist with the given items checked.
+func checklistWith(ids ...string) Checklist {
+	checklist := DefaultChecklist()
+	for _, id := range ids {
+		checklist.Set(id, true)
+	}
+	return checklist
+}
+
+func TestChecklist(t *testing.T) {
+	checklist := checklistWith(ChecklistSelfReview, ChecklistDocumentation, ChecklistReadmeUpdated)
+
+	if !checklist.Checked(ChecklistSelfReview) {
 		t.Error("expected SelfReview to be true")
 	}
-	if checklist.IncludesTesting {
+	if checklist.Checked(ChecklistIncludesTesting) {
 		t.Error("expected IncludesTesting to be false")
 	}
-	if !checklist.Documentation {
+	if !checklist.Checked(ChecklistDocumentation) {
 		t.Error("expected Documentation to be true")
 	}
-	if checklist.EngineerReachout {
+	if checklist.Checked(ChecklistEngineerReachout) {
 		t.Error("expected EngineerReachout to be false")
 	}
-	if !checklist.ReadmeUpdated {
+	if !checklist.Checked(ChecklistReadmeUpdated) {
 		t.Error("expected ReadmeUpdated to be true")
 	}
+	if checklist.Set("unknown", true) {
+		t.Error("expected Set to report unknown items")
+	}
+	if checklist.Checked("unknown") {
+		t.Error("expected unknown items to be unchecked")
+	}
+}
+
+func TestChecklist_Order(t *testing.T) {
+	checklist := DefaultChecklist()
+	want := []string{ChecklistSelfReview, ChecklistIncludesTesting, ChecklistDocumentation, ChecklistEngineerReachout, ChecklistReadmeUpdated}
+	if len(checklist) != len(want) {
+		t.Fatalf("expected %d items, got %d", len(want), len(checklist))
+	}
+	for i, id := range want {
+		if checklist[i].ID != id {
+			t.Errorf("expected %q at index %d, got %q", id, i, checklist[i].ID)
+		}
+	}
+	if !checklist[0].Default {
+		t.Error("expected self-review to default to checked")
+	}
+}
+
+func TestChecklist_Clone(t *testing.T) {
+	original := Checklist{{ID: "deploy", Text: "Deployed", RequiredFor: []string{"main"}}}
+	clone := original.Clone()
+	clone.Set("deploy", true)
+	clone[0].RequiredFor[0] = "develop"
+
+	if original.Checked("deploy") {
+		t.Error("expected clone to be independent of the original")
+	}
+	if original[0].RequiredFor[0] != "main" {
+		t.Error("expected RequiredFor to be copied")
+	}
+	if Checklist(nil).Clone() != nil {
+		t.Error("expected nil clone of nil checklist")
+	}
+}
+
+func TestChecklist_MissingFor(t *testing.T) {
+	checklist := Checklist{
+		{ID: "migration", Text: "I ran the migration on staging", RequiredFor: []string{"main", "release/*"}},
+		{ID: "changelog", Text: "Changelog reviewed", RequiredFor: []string{"main"}, Checked: true},
+		{ID: "docs", Text: "Docs updated"},
+	}
+
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{"main", []string{"migration"}},
+		{"release/1.4", []string{"migration"}},
+		{"develop", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		missing := checklist.MissingFor(tt.target)
+		var ids []string
+		for _, item := range missing {
+			ids = append(ids, item.ID)
+		}
+		if strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("MissingFor(%q) = %v, want %v", tt.target, ids, tt.want)
+		}
+	}
 }
 
 func TestEntry_GenerateMarkdown_WithCommits(t *testing.T) {
@@ -387,9 +527,7 @@ func TestEntry_GenerateBitbucketPR_EmptyFields(t *testing.T) {
 	entry := &Entry{
 		Title:       "Simple fix",
 		Description: "Basic description",
-		Checklist: Checklist{
-			SelfReview: true,
-		},
+		Checklist:   checklistWith(ChecklistSelfReview),
 	}
 
 	selectedTypes := map[string]string{"Bug fix": "Bug fix"}
@@ -429,11 +567,7 @@ func TestEntry_GenerateBitbucketPR(t *testing.T) {
 		Todos:       []string{"Update documentation"},
 		ModelChanges: []string{"Updated User model"},
 		Testing:     []string{"Test login flow", "Test token validation"},
-		Checklist: Checklist{
-			SelfReview:      true,
-			IncludesTesting: true,
-			Documentation:  false,
-		},
+		Checklist:   checklistWith(ChecklistSelfReview, ChecklistIncludesTesting),
 	}
 
 	selectedTypes := map[string]string{
@@ -521,3 +655,47 @@ func TestMetadata_GenerateFilename_Timestamp(t *testing.T) {
 		t.Errorf("expected filename2 to end with %q, got %q", expectedSuffix, filename2)
 	}
 }
+
+func TestEntry_GenerateMarkdown_CustomOptions(t *testing.T) {
+	entry := &Entry{
+		Title:       "Custom",
+		ChangeTypes: []string{"Fix", "Feature"},
+		Checklist: Checklist{
+			{ID: "migration", Text: "I ran the migration on staging", Checked: true},
+		},
+	}
+
+	selectedTypes := map[string]string{"Fix": "Fix", "Feature": ""}
+	markdown := entry.GenerateMarkdown(selectedTypes)
+
+	if !strings.Contains(markdown, "- [x] Fix\n- [ ] Feature\n") {
+		t.Errorf("expected configured change types, got %q", markdown)
+	}
+	if strings.Contains(markdown, "Bug fix") {
+		t.Error("expected default change types to be replaced")
+	}
+	if !strings.Contains(markdown, "## Checklist\n\n- [x] I ran the migration on staging\n\n") {
+		t.Errorf("expected configured checklist only, got %q", markdown)
+	}
+
+	prContent := entry.GenerateBitbucketPR(selectedTypes)
+	if !strings.Contains(prContent, "- ✅ I ran the migration on staging\n") {
+		t.Errorf("expected configured checklist in PR content, got %q", prContent)
+	}
+	if strings.Contains(prContent, "self-review") {
+		t.Error("expected unconfigured checklist items to be omitted")
+	}
+
+	entry.Checklist = Checklist{}
+	if strings.Contains(entry.GenerateMarkdown(selectedTypes), "## Checklist") {
+		t.Error("expected no checklist section for an empty checklist")
+	}
+}
+
+func TestEntry_GenerateMarkdown_NilChecklist(t *testing.T) {
+	entry := &Entry{Title: "Legacy"}
+	markdown := entry.GenerateMarkdown(map[string]string{"Bug fix": "Bug fix"})
+	if !strings.Contains(markdown, "- [ ] I have performed a self-review of my code") {
+		t.Error("expected the default checklist, unchecked, when none is set")
+	}
+}
@@ -0,0 +1,106 @@
+package changelog
+
+import (
+	"path"
+)
+
+// Ids of the built-in checklist items.
+const (
+	ChecklistSelfReview       = "self-review"
+	ChecklistIncludesTesting  = "tests"
+	ChecklistDocumentation    = "documentation"
+	ChecklistEngineerReachout = "engineer-reachout"
+	ChecklistReadmeUpdated    = "readme"
+)
+
+// ChecklistItem is one checklist question and its answer. RequiredFor holds
+// target branch patterns, such as "main" or "release/*", for which the item
+// must be checked.
+type ChecklistItem struct {
+	ID          string   `json:"id" yaml:"id"`
+	Text        string   `json:"text" yaml:"text"`
+	Default     bool     `json:"default,omitempty" yaml:"default,omitempty"`
+	RequiredFor []string `json:"required_for,omitempty" yaml:"required_for,omitempty"`
+	Checked     bool     `json:"checked,omitempty" yaml:"checked,omitempty"`
+}
+
+// Checklist is the ordered list of checklist items of an entry.
+type Checklist []ChecklistItem
+
+// DefaultChecklist returns the built-in checklist, unanswered.
+func DefaultChecklist() Checklist {
+	return Checklist{
+		{ID: ChecklistSelfReview, Text: "I have performed a self-review of my code", Default: true},
+		{ID: ChecklistIncludesTesting, Text: "I have added tests that prove my fix is effective or my feature works"},
+		{ID: ChecklistDocumentation, Text: "I have added necessary documentation (if appropriate)"},
+		{ID: ChecklistEngineerReachout, Text: "I have proactively reached out to an engineer to review this PR"},
+		{ID: ChecklistReadmeUpdated, Text: "I have updated the README file (if appropriate)"},
+	}
+}
+
+// Clone returns a copy of c that can be answered without touching c.
+func (c Checklist) Clone() Checklist {
+	if c == nil {
+		return nil
+	}
+	clone := make(Checklist, len(c))
+	for i, item := range c {
+		item.RequiredFor = append([]string(nil), item.RequiredFor...)
+		clone[i] = item
+	}
+	return clone
+}
+
+// Item returns the item with the given id, or nil when there is none.
+func (c Checklist) Item(id string) *ChecklistItem {
+	for i := range c {
+		if c[i].ID == id {
+			return &c[i]
+		}
+	}
+	return nil
+}
+
+// Checked reports whether the item with the given id is checked.
+func (c Checklist) Checked(id string) bool {
+	if item := c.Item(id); item != nil {
+		return item.Checked
+	}
+	return false
+}
+
+// Set checks or unchecks the item with the given id and reports whether the
+// item exists.
+func (c Checklist) Set(id string, checked bool) bool {
+	item := c.Item(id)
+	if item == nil {
+		return false
+	}
+	item.Checked = checked
+	return true
+}
+
+// IsRequiredFor reports whether the item must be checked when merging into
+// targetBranch.
+func (item ChecklistItem) IsRequiredFor(targetBranch string) bool {
+	if targetBranch == "" {
+		return false
+	}
+	for _, pattern := range item.RequiredFor {
+		if matched, _ := path.Match(pattern, targetBranch); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// MissingFor returns the unchecked items required for targetBranch.
+func (c Checklist) MissingFor(targetBranch string) []ChecklistItem {
+	var missing []ChecklistItem
+	for _, item := range c {
+		if !item.Checked && item.IsRequiredFor(targetBranch) {
+			missing = append(missing, item)
+		}
+	}
+	return missing
+}
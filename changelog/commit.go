@@ -0,0 +1,165 @@
+package changelog
+
+import (
+	"strings"
+	"time"
+
+	"github.com/abirhasanmubin/changelog-go/command"
+)
+
+type GitCommit struct {
+	Hash    string `json:"hash" yaml:"hash"`
+	Message string `json:"message" yaml:"message"`
+	// Body is the rest of the commit message after the subject, trailers
+	// included.
+	Body      string `json:"body,omitempty" yaml:"body,omitempty"`
+	CommitUrl string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	AuthorName  string    `json:"author_name,omitempty" yaml:"author_name,omitempty"`
+	AuthorEmail string    `json:"author_email,omitempty" yaml:"author_email,omitempty"`
+	AuthorDate  time.Time `json:"author_date,omitempty" yaml:"author_date,omitempty"`
+	CommitDate  time.Time `json:"commit_date,omitempty" yaml:"commit_date,omitempty"`
+	// Trailers are the "Key: value" lines closing the message, such as
+	// Co-authored-by, Signed-off-by and Refs, in order.
+	Trailers  []Trailer       `json:"trailers,omitempty" yaml:"trailers,omitempty"`
+	Parents   int             `json:"parents,omitempty" yaml:"parents,omitempty"`
+	Signature SignatureStatus `json:"signature,omitempty" yaml:"signature,omitempty"`
+}
+
+// Trailer is one "Key: value" line of a commit message trailer block.
+type Trailer struct {
+	Key   string `json:"key" yaml:"key"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// SignatureStatus is what git made of a commit's signature. It is empty
+// for unsigned commits.
+type SignatureStatus string
+
+const (
+	SignatureGood SignatureStatus = "good"
+	// SignatureUntrusted is a good signature from a key of unknown validity.
+	SignatureUntrusted  SignatureStatus = "untrusted"
+	SignatureExpired    SignatureStatus = "expired"
+	SignatureExpiredKey SignatureStatus = "expired-key"
+	SignatureRevoked    SignatureStatus = "revoked"
+	SignatureBad        SignatureStatus = "bad"
+	// SignatureUnchecked is a signature that could not be checked, usually
+	// for a missing key.
+	SignatureUnchecked SignatureStatus = "unchecked"
+)
+
+// signatureStatuses maps git's %G? letters to statuses.
+var signatureStatuses = map[string]SignatureStatus{
+	"G": SignatureGood,
+	"U": SignatureUntrusted,
+	"X": SignatureExpired,
+	"Y": SignatureExpiredKey,
+	"R": SignatureRevoked,
+	"B": SignatureBad,
+	"E": SignatureUnchecked,
+}
+
+// Signed reports whether the commit carries a signature, valid or not.
+func (c GitCommit) Signed() bool {
+	return c.Signature != ""
+}
+
+// TrailerValues returns the values of the trailers named key, compared
+// case-insensitively.
+func (c GitCommit) TrailerValues(key string) []string {
+	var values []string
+	for _, trailer := range c.Trailers {
+		if strings.EqualFold(trailer.Key, key) {
+			values = append(values, trailer.Value)
+		}
+	}
+	return values
+}
+
+// CoAuthors returns the Co-authored-by trailers, as "Name <email>".
+func (c GitCommit) CoAuthors() []string {
+	return c.TrailerValues("Co-authored-by")
+}
+
+// Refs returns the references listed in Refs trailers, which may hold
+// several separated by commas.
+func (c GitCommit) Refs() []string {
+	var refs []string
+	for _, value := range c.TrailerValues("Refs") {
+		for _, ref := range strings.Split(value, ",") {
+			if ref = strings.TrimSpace(ref); ref != "" {
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs
+}
+
+// parseCommitLog reads the output of GetCommitLogBetweenBranches.
+func parseCommitLog(log, commitUrl string) []GitCommit {
+	var commits []GitCommit
+	for _, record := range strings.Split(log, command.CommitRecordSeparator) {
+		fields := strings.Split(strings.TrimLeft(record, "\n"), command.CommitFieldSeparator)
+		if len(fields) < 10 || fields[0] == "" {
+			continue
+		}
+		hash := fields[0]
+		commit := GitCommit{
+			Hash:        hash,
+			AuthorName:  fields[1],
+			AuthorEmail: fields[2],
+			AuthorDate:  parseCommitDate(fields[3]),
+			CommitDate:  parseCommitDate(fields[4]),
+			Parents:     len(strings.Fields(fields[5])),
+			Signature:   signatureStatuses[fields[6]],
+			Message:     fields[7],
+			Trailers:    parseTrailers(fields[8]),
+			Body:        strings.TrimSpace(strings.Join(fields[9:], command.CommitFieldSeparator)),
+			CommitUrl:   commitUrl + hash,
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+// BranchCommits lists the commits on branch that targetBranch does not
+// have, newest first, as PopulateCommitHistory records them. Commits that
+// only change excludedPaths are left out.
+func BranchCommits(cmd command.Commands, targetBranch, branch string, excludedPaths ...string) ([]GitCommit, error) {
+	log, err := cmd.GetCommitLogBetweenBranches(targetBranch, branch, excludedPaths...)
+	if err != nil {
+		return nil, err
+	}
+	return parseCommitLog(log, ""), nil
+}
+
+func parseCommitDate(value string) time.Time {
+	date, err := time.Parse(time.RFC3339, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}
+	}
+	return date
+}
+
+// parseTrailers reads the "Key: value" lines of git's
+// %(trailers:only,unfold).
+func parseTrailers(block string) []Trailer {
+	var trailers []Trailer
+	for _, line := range strings.Split(block, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			continue
+		}
+		trailers = append(trailers, Trailer{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value)})
+	}
+	return trailers
+}
+
+// formatDate shows a commit date as 2006-01-02, empty when unknown.
+func formatDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format(time.DateOnly)
+}
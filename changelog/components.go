@@ -0,0 +1,81 @@
+package changelog
+
+import (
+	"path"
+	"strings"
+)
+
+// Component is a part of a monorepo, owning the files matching its paths.
+type Component struct {
+	Name string
+	// Paths are globs relative to the repository root. "**" matches any
+	// number of directories, and a path without wildcards matches that
+	// directory and everything below it.
+	Paths []string
+}
+
+// Owns reports whether file belongs to the component.
+func (c Component) Owns(file string) bool {
+	for _, pattern := range c.Paths {
+		if MatchPath(pattern, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchPath reports whether file, relative to the repository root, matches
+// the component path pattern.
+func MatchPath(pattern, file string) bool {
+	pattern = strings.Trim(strings.TrimPrefix(pattern, "./"), "/")
+	file = strings.Trim(strings.TrimPrefix(file, "./"), "/")
+	if pattern == "" || file == "" {
+		return false
+	}
+	if !strings.ContainsAny(pattern, `*?[\`) {
+		return file == pattern || strings.HasPrefix(file, pattern+"/")
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(file, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := len(name); i >= 0; i-- {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// DetectComponents returns the names of the components owning any of files,
+// in the order of components. A renamed file counts for both its paths.
+func DetectComponents(components []Component, files []FileChange) []string {
+	var names []string
+	for _, component := range components {
+		for _, file := range files {
+			if component.Owns(file.Path) || (file.Renamed() && component.Owns(file.OldPath)) {
+				names = append(names, component.Name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// SuggestedComponents returns the components the branch's changed files
+// touch, as found by PopulateCommitHistory.
+func (e *Entry) SuggestedComponents() []string {
+	return e.touchedComponents
+}
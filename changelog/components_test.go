@@ -0,0 +1,71 @@
+package changelog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		file    string
+		want    bool
+	}{
+		{"services/api", "services/api/main.go", true},
+		{"services/api/", "services/api/handlers/user.go", true},
+		{"./services/api", "services/api/main.go", true},
+		{"services/api", "services/api-gateway/main.go", false},
+		{"services/api/**", "services/api/handlers/user.go", true},
+		{"services/api/**", "services/api", true},
+		{"services/*/main.go", "services/web/main.go", true},
+		{"services/*/main.go", "services/web/cmd/main.go", false},
+		{"**/*.proto", "proto/billing/v1/invoice.proto", true},
+		{"**/*.proto", "invoice.proto", true},
+		{"libs/**/testdata/**", "libs/auth/jwt/testdata/token.json", true},
+		{"libs/**/testdata/**", "libs/auth/jwt/token.go", false},
+		{"", "main.go", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchPath(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("MatchPath(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestDetectComponents(t *testing.T) {
+	components := []Component{
+		{Name: "api", Paths: []string{"services/api"}},
+		{Name: "web", Paths: []string{"services/web/**", "packages/ui/**"}},
+		{Name: "billing", Paths: []string{"services/billing"}},
+		{Name: "proto", Paths: []string{"**/*.proto"}},
+	}
+	files := []FileChange{
+		{Path: "packages/ui/button.tsx"},
+		{Path: "services/api/server.go"},
+		{Path: "shared/billing.go", OldPath: "services/billing/shared.go"},
+		{Path: "README.md"},
+	}
+
+	want := []string{"api", "web", "billing"}
+	if got := DetectComponents(components, files); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := DetectComponents(nil, files); got != nil {
+		t.Errorf("expected no components without definitions, got %v", got)
+	}
+}
+
+func TestEntry_Render_Components(t *testing.T) {
+	entry := Entry{Title: "Shared auth", Components: []string{"api", "web"}}
+
+	pr := entry.GenerateBitbucketPR(nil)
+	if !strings.Contains(pr, "**Components:** api, web\n") {
+		t.Errorf("expected components in PR text, got\n%s", pr)
+	}
+	markdown := entry.GenerateMarkdown(nil)
+	if !strings.Contains(markdown, "## Components\n\n- api\n- web\n\n## Type of change") {
+		t.Errorf("expected a Components section, got\n%s", markdown)
+	}
+}
@@ -0,0 +1,149 @@
+package changelog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Contributor is a commit author or co-author of the entry's branch.
+type Contributor struct {
+	Name  string `json:"name,omitempty" yaml:"name,omitempty"`
+	Email string `json:"email,omitempty" yaml:"email,omitempty"`
+	// Handle is the contributor's user name on the forge, without the "@".
+	Handle string `json:"handle,omitempty" yaml:"handle,omitempty"`
+}
+
+// Mention is how the contributor is credited: "@handle" when the handle
+// is known, the name otherwise.
+func (c Contributor) Mention() string {
+	if c.Handle != "" {
+		return "@" + c.Handle
+	}
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Email
+}
+
+// ContributorOptions configure the Contributors line.
+type ContributorOptions struct {
+	// Skip leaves contributors out of the entry.
+	Skip bool
+	// Handles map email addresses or names, as the mailmap leaves them, to
+	// forge handles. Emails are compared case-insensitively.
+	Handles map[string]string
+}
+
+// Mailmap maps "Name <email>" contacts to canonical ones, in order, such
+// as command.Commands.CheckMailmap.
+type Mailmap func(contacts []string) ([]string, error)
+
+// lookupHandle finds the handle for an email address, then for a name.
+func lookupHandle(handles map[string]string, name, email string) string {
+	for key, handle := range handles {
+		if email != "" && strings.EqualFold(key, email) {
+			return strings.TrimPrefix(handle, "@")
+		}
+	}
+	if handle, ok := handles[name]; ok && name != "" {
+		return strings.TrimPrefix(handle, "@")
+	}
+	return ""
+}
+
+// CollectContributors returns the authors and co-authors of commits, each
+// once in the order first seen, with their handles. Contacts go through
+// mailmap, when given, so that the same person committing under several
+// names or addresses is credited once.
+func CollectContributors(commits []GitCommit, mailmap Mailmap, handles map[string]string) []Contributor {
+	var contacts []string
+	for _, commit := range commits {
+		if commit.AuthorName != "" || commit.AuthorEmail != "" {
+			contacts = append(contacts, formatContact(commit.AuthorName, commit.AuthorEmail))
+		}
+		contacts = append(contacts, commit.CoAuthors()...)
+	}
+	contacts = mapContacts(contacts, mailmap)
+
+	var contributors []Contributor
+	seen := make(map[string]bool)
+	for _, contact := range contacts {
+		name, email := parseContact(contact)
+		key := strings.ToLower(email)
+		if key == "" {
+			key = name
+		}
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		contributors = append(contributors, Contributor{Name: name, Email: email, Handle: lookupHandle(handles, name, email)})
+	}
+	return contributors
+}
+
+// mapContacts runs the contacts with an email address through mailmap,
+// keeping them as given when it fails.
+func mapContacts(contacts []string, mailmap Mailmap) []string {
+	if mailmap == nil {
+		return contacts
+	}
+	var indexes []int
+	var mappable []string
+	for i, contact := range contacts {
+		if _, email := parseContact(contact); email != "" {
+			indexes = append(indexes, i)
+			mappable = append(mappable, contact)
+		}
+	}
+	mapped, err := mailmap(mappable)
+	if err != nil || len(mapped) != len(mappable) {
+		return contacts
+	}
+	result := append([]string(nil), contacts...)
+	for i, index := range indexes {
+		result[index] = mapped[i]
+	}
+	return result
+}
+
+func formatContact(name, email string) string {
+	if email == "" {
+		return name
+	}
+	if name == "" {
+		return "<" + email + ">"
+	}
+	return fmt.Sprintf("%s <%s>", name, email)
+}
+
+// parseContact splits "Name <email>", also accepting a bare name or
+// address.
+func parseContact(contact string) (name, email string) {
+	contact = strings.TrimSpace(contact)
+	if open := strings.LastIndex(contact, "<"); open >= 0 && strings.HasSuffix(contact, ">") {
+		return strings.TrimSpace(contact[:open]), strings.TrimSpace(contact[open+1 : len(contact)-1])
+	}
+	if strings.Contains(contact, "@") && !strings.ContainsAny(contact, " \t") {
+		return "", contact
+	}
+	return contact, ""
+}
+
+// MergeContributors combines lists of contributors, each once in the order
+// first seen, as credited by Mention.
+func MergeContributors(lists ...[]Contributor) []Contributor {
+	var merged []Contributor
+	seen := make(map[string]bool)
+	for _, list := range lists {
+		for _, contributor := range list {
+			key := strings.ToLower(contributor.Mention())
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, contributor)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,92 @@
+package changelog
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCollectContributors(t *testing.T) {
+	commits := []GitCommit{
+		{AuthorName: "jdoe", AuthorEmail: "jdoe@laptop.local", Trailers: []Trailer{
+			{Key: "Co-authored-by", Value: "Bob Stone <bob@example.com>"},
+			{Key: "Co-authored-by", Value: "Pair Bot"},
+		}},
+		{AuthorName: "Jane Doe", AuthorEmail: "Jane@Example.com"},
+		{AuthorName: "Bob Stone", AuthorEmail: "BOB@example.com"},
+	}
+	mailmap := func(contacts []string) ([]string, error) {
+		mapped := make([]string, len(contacts))
+		for i, contact := range contacts {
+			mapped[i] = strings.Replace(contact, "jdoe <jdoe@laptop.local>", "Jane Doe <jane@example.com>", 1)
+		}
+		return mapped, nil
+	}
+	handles := map[string]string{"JANE@example.com": "janedoe", "Pair Bot": "@pair-bot"}
+
+	tests := []struct {
+		name     string
+		mailmap  Mailmap
+		expected []Contributor
+	}{
+		{
+			name:    "mapped",
+			mailmap: mailmap,
+			expected: []Contributor{
+				{Name: "Jane Doe", Email: "jane@example.com", Handle: "janedoe"},
+				{Name: "Bob Stone", Email: "bob@example.com"},
+				{Name: "Pair Bot", Handle: "pair-bot"},
+			},
+		},
+		{
+			name:    "failing mailmap",
+			mailmap: func([]string) ([]string, error) { return nil, errors.New("no git") },
+			expected: []Contributor{
+				{Name: "jdoe", Email: "jdoe@laptop.local"},
+				{Name: "Bob Stone", Email: "bob@example.com"},
+				{Name: "Pair Bot", Handle: "pair-bot"},
+				{Name: "Jane Doe", Email: "Jane@Example.com", Handle: "janedoe"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contributors := CollectContributors(commits, tt.mailmap, handles)
+			if !reflect.DeepEqual(contributors, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, contributors)
+			}
+		})
+	}
+}
+
+func TestMergeContributors(t *testing.T) {
+	merged := MergeContributors(
+		[]Contributor{{Handle: "jane"}, {Name: "Bob Stone"}},
+		[]Contributor{{Name: "Jane Doe", Handle: "Jane"}, {Name: "Carol"}},
+	)
+	expected := []Contributor{{Handle: "jane"}, {Name: "Bob Stone"}, {Name: "Carol"}}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("expected %+v, got %+v", expected, merged)
+	}
+}
+
+func TestEntry_Render_Contributors(t *testing.T) {
+	entry := Entry{
+		Title: "Refunds",
+		Metadata: Metadata{Contributors: []Contributor{
+			{Name: "Jane Doe", Handle: "jane"},
+			{Name: "Bob Stone", Email: "bob@example.com"},
+		}},
+	}
+
+	pr := entry.GenerateBitbucketPR(nil)
+	if !strings.Contains(pr, "**Contributors:** @jane, Bob Stone\n") {
+		t.Errorf("expected contributors in PR text, got\n%s", pr)
+	}
+	markdown := entry.GenerateMarkdown(nil)
+	if !strings.Contains(markdown, "## Contributors\n\n@jane, Bob Stone\n") {
+		t.Errorf("expected a Contributors section, got\n%s", markdown)
+	}
+}
@@ -0,0 +1,186 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	conventionalHeaderPattern = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()]*)\))?(!)?: (.+)$`)
+	breakingFooterPattern     = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+)
+
+// conventionalChangeTypes maps Conventional Commits types to the default
+// change types they imply.
+var conventionalChangeTypes = map[string]string{
+	"fix":      "Bug fix",
+	"feat":     "New feature",
+	"refactor": "Code refactor",
+	"docs":     "Documentation update",
+}
+
+const breakingChangeType = "Breaking change"
+
+// ConventionalCommit is a commit message following the Conventional Commits
+// specification: "type(scope)!: description".
+type ConventionalCommit struct {
+	Type        string
+	Scope       string
+	Description string
+	// Breaking is set by a "!" after the type or scope, or by a
+	// "BREAKING CHANGE:" footer.
+	Breaking bool
+}
+
+// ChangeType returns the default change type the commit type implies, empty
+// when there is none.
+func (c ConventionalCommit) ChangeType() string {
+	return conventionalChangeTypes[c.Type]
+}
+
+// ParseConventionalCommit reads a commit subject and body. ok is false when
+// the subject does not follow the specification.
+func ParseConventionalCommit(subject, body string) (commit ConventionalCommit, ok bool) {
+	match := conventionalHeaderPattern.FindStringSubmatch(strings.TrimSpace(subject))
+	if match == nil {
+		return ConventionalCommit{}, false
+	}
+	return ConventionalCommit{
+		Type:        strings.ToLower(match[1]),
+		Scope:       match[2],
+		Description: strings.TrimSpace(match[4]),
+		Breaking:    match[3] == "!" || breakingFooterPattern.MatchString(body),
+	}, true
+}
+
+// Conventional parses the commit's message as a Conventional Commit.
+func (c GitCommit) Conventional() (ConventionalCommit, bool) {
+	return ParseConventionalCommit(c.Message, c.Body)
+}
+
+// SuggestedChangeTypes selects the change types the entry's commits imply,
+// in the form the wizard returns. Types the entry is not asked with are
+// left out; the result is empty when no commit implies any.
+func (e *Entry) SuggestedChangeTypes() map[string]string {
+	implied := e.impliedChangeTypes()
+	suggested := make(map[string]string)
+	for _, changeType := range e.changeTypes() {
+		if len(implied[changeType]) > 0 {
+			suggested[changeType] = changeType
+		}
+	}
+	if len(suggested) == 0 {
+		return suggested
+	}
+	for _, changeType := range e.changeTypes() {
+		if _, ok := suggested[changeType]; !ok {
+			suggested[changeType] = ""
+		}
+	}
+	return suggested
+}
+
+// impliedChangeTypes maps every change type implied by a commit to the
+// commits implying it.
+func (e *Entry) impliedChangeTypes() map[string][]GitCommit {
+	implied := make(map[string][]GitCommit)
+	for _, commit := range e.Metadata.Commits {
+		conventional, ok := commit.Conventional()
+		if !ok {
+			continue
+		}
+		if changeType := conventional.ChangeType(); changeType != "" {
+			implied[changeType] = append(implied[changeType], commit)
+		}
+		if conventional.Breaking {
+			implied[breakingChangeType] = append(implied[breakingChangeType], commit)
+		}
+	}
+	return implied
+}
+
+// ChangeTypeConflicts describes where selectedTypes contradict the entry's
+// commits: an implied type left unselected, or a type selected although
+// every commit is conventional and none implies it.
+func (e *Entry) ChangeTypeConflicts(selectedTypes map[string]string) []string {
+	implied := e.impliedChangeTypes()
+	allConventional := len(e.Metadata.Commits) > 0
+	for _, commit := range e.Metadata.Commits {
+		if _, ok := commit.Conventional(); !ok {
+			allConventional = false
+		}
+	}
+
+	var conflicts []string
+	for _, changeType := range e.changeTypes() {
+		selected := selectedTypes[changeType] != ""
+		commits := implied[changeType]
+		switch {
+		case !selected && len(commits) > 0:
+			conflicts = append(conflicts, fmt.Sprintf("%q is not selected but commit %s is %s", changeType, shortHash(commits[0].Hash), describeImplied(changeType)))
+		case selected && len(commits) == 0 && allConventional && isImpliable(changeType):
+			conflicts = append(conflicts, fmt.Sprintf("%q is selected but no commit is %s", changeType, describeImplied(changeType)))
+		}
+	}
+	return conflicts
+}
+
+func isImpliable(changeType string) bool {
+	if changeType == breakingChangeType {
+		return true
+	}
+	for _, implied := range conventionalChangeTypes {
+		if implied == changeType {
+			return true
+		}
+	}
+	return false
+}
+
+func describeImplied(changeType string) string {
+	if changeType == breakingChangeType {
+		return "marked as breaking"
+	}
+	for commitType, implied := range conventionalChangeTypes {
+		if implied == changeType {
+			return fmt.Sprintf("a %q commit", commitType)
+		}
+	}
+	return changeType
+}
+
+// SuggestedTitle proposes a title: the description of the only commit, or
+// the branch name spelled out. It is empty when neither gives one.
+func (e *Entry) SuggestedTitle() string {
+	if len(e.Metadata.Commits) == 1 {
+		commit := e.Metadata.Commits[0]
+		if conventional, ok := commit.Conventional(); ok {
+			return capitalize(conventional.Description)
+		}
+		return capitalize(strings.TrimSpace(commit.Message))
+	}
+	return titleFromBranch(e.Metadata.Branch)
+}
+
+// titleFromBranch turns "feature/add-login_page" into "Add login page".
+func titleFromBranch(branch string) string {
+	if i := strings.LastIndex(branch, "/"); i >= 0 {
+		branch = branch[i+1:]
+	}
+	words := strings.FieldsFunc(branch, func(r rune) bool { return r == '-' || r == '_' })
+	if len(words) == 0 || branch == "HEAD" {
+		return ""
+	}
+	return capitalize(strings.Join(words, " "))
+}
+
+func capitalize(text string) string {
+	r, size := utf8.DecodeRuneInString(text)
+	if r == utf8.RuneError {
+		return text
+	}
+	return string(unicode.ToUpper(r)) + text[size:]
+}
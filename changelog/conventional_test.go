@@ -0,0 +1,137 @@
+package changelog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConventionalCommit(t *testing.T) {
+	tests := []struct {
+		name     string
+		subject  string
+		body     string
+		expected ConventionalCommit
+		ok       bool
+	}{
+		{"type only", "fix: handle nil user", "", ConventionalCommit{Type: "fix", Description: "handle nil user"}, true},
+		{"scope", "feat(auth): add login", "", ConventionalCommit{Type: "feat", Scope: "auth", Description: "add login"}, true},
+		{"bang", "refactor(api)!: drop v1", "", ConventionalCommit{Type: "refactor", Scope: "api", Description: "drop v1", Breaking: true}, true},
+		{"footer", "feat: new config", "Details\n\nBREAKING CHANGE: old keys removed", ConventionalCommit{Type: "feat", Description: "new config", Breaking: true}, true},
+		{"hyphenated footer", "feat: new config", "BREAKING-CHANGE: old keys removed", ConventionalCommit{Type: "feat", Description: "new config", Breaking: true}, true},
+		{"footer mid-line", "docs: mention BREAKING CHANGE: x", "see BREAKING CHANGE: notes", ConventionalCommit{Type: "docs", Description: "mention BREAKING CHANGE: x"}, true},
+		{"uppercase type", "Fix: typo", "", ConventionalCommit{Type: "fix", Description: "typo"}, true},
+		{"plain message", "Fix login bug", "", ConventionalCommit{}, false},
+		{"missing space", "fix:typo", "", ConventionalCommit{}, false},
+		{"merge", "Merge branch 'main' into feature", "", ConventionalCommit{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commit, ok := ParseConventionalCommit(tt.subject, tt.body)
+			if ok != tt.ok || commit != tt.expected {
+				t.Errorf("expected %+v, %v, got %+v, %v", tt.expected, tt.ok, commit, ok)
+			}
+		})
+	}
+}
+
+func entryWithCommits(branch string, messages ...string) Entry {
+	entry := Entry{Metadata: Metadata{Branch: branch}}
+	for i, message := range messages {
+		subject, body, _ := strings.Cut(message, "\n\n")
+		entry.Metadata.Commits = append(entry.Metadata.Commits, GitCommit{Hash: strings.Repeat(string(rune('a'+i)), 10), Message: subject, Body: body})
+	}
+	return entry
+}
+
+func TestEntry_SuggestedChangeTypes(t *testing.T) {
+	entry := entryWithCommits("feature/login", "feat(auth): add login", "fix: typo", "feat!: drop sessions", "Update deps")
+
+	expected := map[string]string{
+		"Bug fix": "Bug fix", "New feature": "New feature", "Code refactor": "",
+		"Breaking change": "Breaking change", "Documentation update": "", "Other": "",
+	}
+	if suggested := entry.SuggestedChangeTypes(); !reflect.DeepEqual(suggested, expected) {
+		t.Errorf("expected %v, got %v", expected, suggested)
+	}
+
+	entry.ChangeTypes = []string{"Bug fix", "Chore"}
+	expected = map[string]string{"Bug fix": "Bug fix", "Chore": ""}
+	if suggested := entry.SuggestedChangeTypes(); !reflect.DeepEqual(suggested, expected) {
+		t.Errorf("expected only configured types, got %v", suggested)
+	}
+
+	plain := entryWithCommits("feature/login", "Update deps", "chore: bump version")
+	if suggested := plain.SuggestedChangeTypes(); len(suggested) != 0 {
+		t.Errorf("expected no suggestion, got %v", suggested)
+	}
+}
+
+func TestEntry_ChangeTypeConflicts(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    Entry
+		selected map[string]string
+		expected []string
+	}{
+		{
+			name:     "matching",
+			entry:    entryWithCommits("b", "fix: typo"),
+			selected: map[string]string{"Bug fix": "Bug fix"},
+		},
+		{
+			name:     "unselected breaking change",
+			entry:    entryWithCommits("b", "fix: typo", "feat: config\n\nBREAKING CHANGE: keys renamed"),
+			selected: map[string]string{"Bug fix": "Bug fix", "New feature": "New feature"},
+			expected: []string{`"Breaking change" is not selected but commit bbbbbbb is marked as breaking`},
+		},
+		{
+			name:     "selected without commit",
+			entry:    entryWithCommits("b", "docs: readme"),
+			selected: map[string]string{"Documentation update": "Documentation update", "New feature": "New feature", "Other": "Security"},
+			expected: []string{`"New feature" is selected but no commit is a "feat" commit`},
+		},
+		{
+			name:     "selected with plain commits",
+			entry:    entryWithCommits("b", "docs: readme", "Tweak styles"),
+			selected: map[string]string{"Documentation update": "Documentation update", "Bug fix": "Bug fix"},
+		},
+		{
+			name:     "no commits",
+			entry:    entryWithCommits("b"),
+			selected: map[string]string{"Bug fix": "Bug fix"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if conflicts := tt.entry.ChangeTypeConflicts(tt.selected); !reflect.DeepEqual(conflicts, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, conflicts)
+			}
+		})
+	}
+}
+
+func TestEntry_SuggestedTitle(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    Entry
+		expected string
+	}{
+		{"single conventional commit", entryWithCommits("feature/x", "feat(auth): add login page"), "Add login page"},
+		{"single plain commit", entryWithCommits("feature/x", "fix the build"), "Fix the build"},
+		{"branch name", entryWithCommits("feature/add-login_page", "feat: a", "fix: b"), "Add login page"},
+		{"branch without prefix", entryWithCommits("cleanup-logs"), "Cleanup logs"},
+		{"detached", entryWithCommits("HEAD"), ""},
+		{"no branch", entryWithCommits(""), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if title := tt.entry.SuggestedTitle(); title != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, title)
+			}
+		})
+	}
+}
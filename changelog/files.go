@@ -0,0 +1,111 @@
+package changelog
+
+import (
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FileChange is a file changed on the branch, as counted by git diff
+// --numstat.
+type FileChange struct {
+	Path string `json:"path" yaml:"path"`
+	// OldPath is where a renamed file came from, empty otherwise.
+	OldPath string `json:"old_path,omitempty" yaml:"old_path,omitempty"`
+	Added   int    `json:"added" yaml:"added"`
+	Deleted int    `json:"deleted" yaml:"deleted"`
+	// Binary files have no line counts.
+	Binary bool `json:"binary,omitempty" yaml:"binary,omitempty"`
+}
+
+// Renamed reports whether the file was moved.
+func (f FileChange) Renamed() bool {
+	return f.OldPath != "" && f.OldPath != f.Path
+}
+
+// FileListOptions configure the Files changed section.
+type FileListOptions struct {
+	// Skip leaves changed files out of the entry.
+	Skip bool
+	// CollapseAfter shortens the section when more files changed: entry
+	// files fold the list away and PR text lists directories only. Zero
+	// never collapses.
+	CollapseAfter int
+}
+
+// FileGroup is the changed files in one directory.
+type FileGroup struct {
+	// Dir is the directory with a trailing slash, "./" for the root.
+	Dir     string
+	Files   []FileChange
+	Added   int
+	Deleted int
+}
+
+// ChangedFiles are the changed files grouped by directory, with totals,
+// as templates see them.
+type ChangedFiles struct {
+	Groups    []FileGroup
+	Count     int
+	Added     int
+	Deleted   int
+	Collapsed bool
+}
+
+// groupFiles sorts files into directories, both in path order.
+func groupFiles(files []FileChange, collapseAfter int) ChangedFiles {
+	changed := ChangedFiles{
+		Count:     len(files),
+		Collapsed: collapseAfter > 0 && len(files) > collapseAfter,
+	}
+	groups := make(map[string]*FileGroup)
+	for _, file := range files {
+		dir := path.Dir(file.Path) + "/"
+		group, ok := groups[dir]
+		if !ok {
+			group = &FileGroup{Dir: dir}
+			groups[dir] = group
+		}
+		group.Files = append(group.Files, file)
+		group.Added += file.Added
+		group.Deleted += file.Deleted
+		changed.Added += file.Added
+		changed.Deleted += file.Deleted
+	}
+	for _, group := range groups {
+		sort.Slice(group.Files, func(i, j int) bool { return group.Files[i].Path < group.Files[j].Path })
+		changed.Groups = append(changed.Groups, *group)
+	}
+	sort.Slice(changed.Groups, func(i, j int) bool { return changed.Groups[i].Dir < changed.Groups[j].Dir })
+	return changed
+}
+
+// parseNumstat reads the output of GetDiffNumstat.
+func parseNumstat(numstat string) []FileChange {
+	fields := strings.Split(numstat, "\x00")
+	var files []FileChange
+	for i := 0; i < len(fields); i++ {
+		counts := strings.SplitN(strings.TrimLeft(fields[i], "\n"), "\t", 3)
+		if len(counts) != 3 {
+			continue
+		}
+		file := FileChange{Path: counts[2]}
+		if file.Path == "" {
+			// A rename: the old and new paths follow as their own fields.
+			if i+2 >= len(fields) {
+				break
+			}
+			file.OldPath, file.Path = fields[i+1], fields[i+2]
+			i += 2
+		}
+		if counts[0] == "-" && counts[1] == "-" {
+			file.Binary = true
+		} else {
+			file.Added, _ = strconv.Atoi(counts[0])
+			file.Deleted, _ = strconv.Atoi(counts[1])
+		}
+		files = append(files, file)
+	}
+	return files
+}
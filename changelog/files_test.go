@@ -0,0 +1,91 @@
+package changelog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseNumstat(t *testing.T) {
+	numstat := "12\t3\tcli/login.go\x00" +
+		"1\t1\t\x00cli/session.go\x00cli/auth.go\x00" +
+		"-\t-\tlogo.png\x00" +
+		"0\t4\tREADME.md\x00"
+
+	expected := []FileChange{
+		{Path: "cli/login.go", Added: 12, Deleted: 3},
+		{Path: "cli/auth.go", OldPath: "cli/session.go", Added: 1, Deleted: 1},
+		{Path: "logo.png", Binary: true},
+		{Path: "README.md", Deleted: 4},
+	}
+	if files := parseNumstat(numstat); !reflect.DeepEqual(files, expected) {
+		t.Errorf("expected %+v, got %+v", expected, files)
+	}
+	if files := parseNumstat(""); files != nil {
+		t.Errorf("expected no files, got %+v", files)
+	}
+}
+
+func TestGroupFiles(t *testing.T) {
+	files := []FileChange{
+		{Path: "cli/login.go", Added: 12, Deleted: 3},
+		{Path: "README.md", Deleted: 4},
+		{Path: "cli/auth.go", OldPath: "cli/session.go", Added: 1, Deleted: 1},
+	}
+
+	changed := groupFiles(files, 2)
+	if changed.Count != 3 || changed.Added != 13 || changed.Deleted != 8 || !changed.Collapsed {
+		t.Errorf("unexpected totals %+v", changed)
+	}
+	if len(changed.Groups) != 2 || changed.Groups[0].Dir != "./" || changed.Groups[1].Dir != "cli/" {
+		t.Fatalf("unexpected groups %+v", changed.Groups)
+	}
+	cli := changed.Groups[1]
+	if cli.Added != 13 || cli.Deleted != 4 || cli.Files[0].Path != "cli/auth.go" || !cli.Files[0].Renamed() {
+		t.Errorf("unexpected cli group %+v", cli)
+	}
+	if groupFiles(files, 3).Collapsed || groupFiles(files, 0).Collapsed {
+		t.Error("expected to collapse only beyond the threshold")
+	}
+}
+
+func TestEntry_Render_Files(t *testing.T) {
+	entry := Entry{
+		Title: "Files",
+		Metadata: Metadata{Files: []FileChange{
+			{Path: "cli/login.go", Added: 12, Deleted: 3},
+			{Path: "cli/auth.go", OldPath: "cli/session.go", Added: 1, Deleted: 1},
+			{Path: "logo.png", Binary: true},
+		}},
+	}
+
+	markdown := entry.GenerateMarkdown(nil)
+	want := "## Files changed\n\n3 files changed, 13 insertions(+), 4 deletions(-)\n\n" +
+		"- `./` 1 file, +0 -0\n  - `logo.png` binary\n" +
+		"- `cli/` 2 files, +13 -4\n  - `cli/session.go` → `cli/auth.go` +1 -1\n  - `cli/login.go` +12 -3\n"
+	if !strings.Contains(markdown, want) {
+		t.Errorf("expected markdown to contain\n%s\ngot\n%s", want, markdown)
+	}
+
+	entry.FileList.CollapseAfter = 2
+	markdown = entry.GenerateMarkdown(nil)
+	if !strings.Contains(markdown, "<details>\n<summary>3 files changed, 13 insertions(+), 4 deletions(-)</summary>\n\n- `./`") ||
+		!strings.Contains(markdown, "`cli/login.go` +12 -3\n</details>\n") {
+		t.Errorf("expected a folded file list, got\n%s", markdown)
+	}
+	parsed, _, err := Parse(markdown)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(parsed.Metadata.Files) != 3 {
+		t.Errorf("expected the folded files to be read back, got %+v", parsed.Metadata.Files)
+	}
+
+	pr, err := entry.Render(TemplatePR, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(pr, "**Files changed:** 3 files, +13 -4\n- `./` 1 file, +0 -0\n- `cli/` 2 files, +13 -4\n") || strings.Contains(pr, "login.go") {
+		t.Errorf("expected collapsed PR text to list directories only, got\n%s", pr)
+	}
+}
@@ -0,0 +1,135 @@
+package changelog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FrontMatterVersion is the version of the front matter SaveToFile writes.
+// Readers refuse newer versions.
+const FrontMatterVersion = 1
+
+// Front matter formats.
+const (
+	FrontMatterYAML = "yaml"
+	FrontMatterJSON = "json"
+	FrontMatterNone = "none"
+)
+
+// FrontMatterFormats lists every supported front matter format.
+var FrontMatterFormats = []string{FrontMatterYAML, FrontMatterJSON, FrontMatterNone}
+
+const frontMatterDelimiter = "---"
+
+// Predefined errors
+var (
+	UnsupportedFrontMatterError = errors.New("unsupported front matter")
+)
+
+// FrontMatter is the machine-readable block at the top of a saved entry.
+type FrontMatter struct {
+	Version   int       `json:"version" yaml:"version"`
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+	// Types are the selected change types, "Other" as "Other: <text>".
+	Types []string `json:"types" yaml:"types"`
+	Entry `yaml:",inline"`
+}
+
+// FrontMatter returns the front matter describing the entry.
+func (e *Entry) FrontMatter(selectedTypes map[string]string) FrontMatter {
+	createdAt, ok := CreatedAt(e.Filename)
+	if !ok {
+		createdAt = time.Now()
+	}
+	frontMatter := FrontMatter{
+		Version:   FrontMatterVersion,
+		CreatedAt: createdAt.UTC(),
+		Types:     []string{},
+		Entry:     *e,
+	}
+	frontMatter.Checklist = e.checklist()
+	frontMatter.ChangeTypes = e.changeTypes()
+	for _, changeType := range e.ChangeTypeOptions(selectedTypes) {
+		if changeType.Selected {
+			frontMatter.Types = append(frontMatter.Types, changeType.String())
+		}
+	}
+	return frontMatter
+}
+
+// GenerateFile renders the entry file: the front matter in the entry's
+// format followed by the markdown body.
+func (e *Entry) GenerateFile(selectedTypes map[string]string) (string, error) {
+	body, err := e.Render(TemplateMarkdown, selectedTypes)
+	if err != nil {
+		return "", fmt.Errorf("failed to render %s template: %v", TemplateMarkdown, err)
+	}
+
+	var encoded []byte
+	switch e.FrontMatterFormat {
+	case "", FrontMatterYAML:
+		encoded, err = yaml.Marshal(e.FrontMatter(selectedTypes))
+	case FrontMatterJSON:
+		encoded, err = json.MarshalIndent(e.FrontMatter(selectedTypes), "", "  ")
+		encoded = append(encoded, '\n')
+	case FrontMatterNone:
+		return body, nil
+	default:
+		return "", fmt.Errorf("%w format %q", UnsupportedFrontMatterError, e.FrontMatterFormat)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to encode front matter: %w", err)
+	}
+	return frontMatterDelimiter + "\n" + string(encoded) + frontMatterDelimiter + "\n\n" + body, nil
+}
+
+// splitFrontMatter separates a leading front matter block from the body.
+// The block is nil when the content has none.
+func splitFrontMatter(content string) ([]byte, string, error) {
+	rest, ok := strings.CutPrefix(content, frontMatterDelimiter+"\n")
+	if !ok {
+		return nil, content, nil
+	}
+	block, body, found := strings.Cut(rest, "\n"+frontMatterDelimiter+"\n")
+	if !found {
+		return nil, "", fmt.Errorf("%w: front matter is not closed", InvalidEntryError)
+	}
+	return []byte(block), strings.TrimPrefix(body, "\n"), nil
+}
+
+// parseFrontMatter decodes a front matter block. JSON blocks are read by the
+// YAML decoder as well.
+func parseFrontMatter(block []byte) (Entry, map[string]string, error) {
+	var frontMatter FrontMatter
+	decoder := yaml.NewDecoder(bytes.NewReader(block))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&frontMatter); err != nil {
+		return Entry{}, nil, fmt.Errorf("%w: %v", InvalidEntryError, err)
+	}
+	if frontMatter.Version < 1 || frontMatter.Version > FrontMatterVersion {
+		return Entry{}, nil, fmt.Errorf("%w version %d, expected at most %d", UnsupportedFrontMatterError, frontMatter.Version, FrontMatterVersion)
+	}
+
+	entry := frontMatter.Entry
+	if entry.Checklist == nil {
+		entry.Checklist = Checklist{}
+	}
+	selectedTypes := make(map[string]string)
+	for _, changeType := range entry.changeTypes() {
+		selectedTypes[changeType] = ""
+	}
+	for _, value := range frontMatter.Types {
+		if name, detail, ok := strings.Cut(value, ": "); ok {
+			selectedTypes[name] = detail
+		} else {
+			selectedTypes[value] = value
+		}
+	}
+	return entry, selectedTypes, nil
+}
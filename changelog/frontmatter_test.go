@@ -0,0 +1,151 @@
+package changelog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func frontMatterEntry() Entry {
+	return Entry{
+		Title:       "Fix login",
+		Description: "Refresh tokens\nbefore expiry",
+		Testing:     []string{"Log in"},
+		Filename:    "1700000000_user_fix-login.md",
+		Checklist:   checklistWith(ChecklistSelfReview),
+		Metadata: Metadata{
+			Branch:       "fix/login",
+			TargetBranch: "main",
+			UserName:     "user",
+			CommitUrl:    "https://example.com/commit/",
+			Commits: []GitCommit{{
+				Hash:        "abcdef1234567",
+				Message:     "Fix login",
+				CommitUrl:   "https://example.com/commit/abcdef1234567",
+				AuthorName:  "Jane",
+				AuthorEmail: "jane@example.com",
+				AuthorDate:  time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+				CommitDate:  time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC),
+				Trailers:    []Trailer{{Key: "Refs", Value: "#12"}},
+				Parents:     1,
+				Signature:   SignatureGood,
+			}},
+		},
+	}
+}
+
+func TestEntry_GenerateFile_FrontMatter(t *testing.T) {
+	selectedTypes := map[string]string{"Bug fix": "Bug fix", "Other": "Security"}
+
+	for _, format := range []string{FrontMatterYAML, FrontMatterJSON} {
+		t.Run(format, func(t *testing.T) {
+			entry := frontMatterEntry()
+			entry.FrontMatterFormat = format
+			content, err := entry.GenerateFile(selectedTypes)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.HasPrefix(content, "---\n") || !strings.HasSuffix(content, entry.GenerateMarkdown(selectedTypes)) {
+				t.Errorf("expected front matter followed by the markdown body, got:\n%s", content)
+			}
+
+			parsed, parsedTypes, err := Parse(content)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(parsed.Metadata, entry.Metadata) {
+				t.Errorf("expected metadata %+v, got %+v", entry.Metadata, parsed.Metadata)
+			}
+			if parsed.Title != entry.Title || parsed.Description != entry.Description || !reflect.DeepEqual(parsed.Testing, entry.Testing) {
+				t.Errorf("unexpected entry %+v", parsed)
+			}
+			if !parsed.Checklist.Checked(ChecklistSelfReview) || parsed.Checklist.Checked(ChecklistIncludesTesting) {
+				t.Errorf("unexpected checklist %+v", parsed.Checklist)
+			}
+			if parsedTypes["Bug fix"] != "Bug fix" || parsedTypes["Other"] != "Security" || parsedTypes["New feature"] != "" {
+				t.Errorf("unexpected selected types %v", parsedTypes)
+			}
+			if parsed.GenerateMarkdown(parsedTypes) != entry.GenerateMarkdown(selectedTypes) {
+				t.Error("expected the body to render the same from front matter")
+			}
+		})
+	}
+}
+
+func TestEntry_GenerateFile_Formats(t *testing.T) {
+	entry := frontMatterEntry()
+
+	entry.FrontMatterFormat = FrontMatterNone
+	content, err := entry.GenerateFile(nil)
+	if err != nil || content != entry.GenerateMarkdown(nil) {
+		t.Errorf("expected plain markdown without front matter, got %q, %v", content, err)
+	}
+
+	entry.FrontMatterFormat = "toml"
+	if _, err := entry.GenerateFile(nil); !errors.Is(err, UnsupportedFrontMatterError) {
+		t.Errorf("expected UnsupportedFrontMatterError, got %v", err)
+	}
+}
+
+func TestEntry_FrontMatter(t *testing.T) {
+	entry := frontMatterEntry()
+	frontMatter := entry.FrontMatter(map[string]string{"New feature": "New feature"})
+
+	if frontMatter.Version != FrontMatterVersion {
+		t.Errorf("expected version %d, got %d", FrontMatterVersion, frontMatter.Version)
+	}
+	if !frontMatter.CreatedAt.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("expected creation time from the filename, got %v", frontMatter.CreatedAt)
+	}
+	if !reflect.DeepEqual(frontMatter.Types, []string{"New feature"}) {
+		t.Errorf("unexpected types %v", frontMatter.Types)
+	}
+	if !reflect.DeepEqual(frontMatter.ChangeTypes, DefaultChangeTypes) {
+		t.Errorf("expected the default change types to be recorded, got %v", frontMatter.ChangeTypes)
+	}
+}
+
+func TestParse_FrontMatterErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    error
+	}{
+		{"unclosed", "---\nversion: 1\n## Title\n", InvalidEntryError},
+		{"unknown key", "---\nversion: 1\ntitel: x\n---\n", InvalidEntryError},
+		{"newer version", "---\nversion: 99\ntitle: x\n---\n", UnsupportedFrontMatterError},
+		{"missing version", "---\ntitle: x\n---\n", UnsupportedFrontMatterError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Parse(tt.content); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSaveToFile_FrontMatter(t *testing.T) {
+	dir := t.TempDir()
+	entry := frontMatterEntry()
+	if err := entry.SaveToFile(map[string]string{"Bug fix": "Bug fix"}, dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, entry.Filename))
+	if err != nil {
+		t.Fatalf("failed to read entry: %v", err)
+	}
+	if !strings.HasPrefix(string(content), "---\nversion: 1\n") {
+		t.Errorf("expected YAML front matter by default, got:\n%s", content)
+	}
+	parsed, _, err := ParseFile(filepath.Join(dir, entry.Filename))
+	if err != nil || parsed.Metadata.Branch != "fix/login" {
+		t.Errorf("expected the saved entry to parse, got %+v, %v", parsed.Metadata, err)
+	}
+}
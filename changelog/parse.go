@@ -0,0 +1,291 @@
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Predefined errors
+var (
+	InvalidEntryError = errors.New("invalid changelog entry")
+)
+
+var (
+	commitLinePattern   = regexp.MustCompile(`^- \[([^\]]*)\]\((\S*?)\) (.*)$`)
+	commitRangePattern  = regexp.MustCompile(`^Commits from '(.*)' to '(.*)':$`)
+	commitBranchPattern = regexp.MustCompile(`^Commits from branch '(.*)':$`)
+	testingStepPattern  = regexp.MustCompile(`^\d+\. (.*)$`)
+	ticketLinePattern   = regexp.MustCompile(`^- (?:\[([^\]]+)\]\((\S*)\)|(\S+))$`)
+	fileLinePattern     = regexp.MustCompile("^  - (?:`([^`]+)` → )?`([^`]+)` (?:binary|\\+(\\d+) -(\\d+))$")
+	fileSummaryPattern  = regexp.MustCompile("^(?:<summary>)?\\d+ files? changed, |^- `[^`]*/` \\d+ files?, |^</?details>$")
+)
+
+// ParseFile reads an entry saved by SaveToFile.
+func ParseFile(path string) (Entry, map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Entry{}, nil, err
+	}
+	entry, selectedTypes, err := Parse(string(content))
+	if err != nil {
+		return Entry{}, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	entry.Filename = filepath.Base(path)
+	return entry, selectedTypes, nil
+}
+
+// Parse turns a saved entry back into an entry and its selected change
+// types. Front matter, when present, is authoritative. Otherwise the
+// markdown produced by the built-in markdown template is read, so that
+// rendering the result gives the same markdown again.
+func Parse(content string) (Entry, map[string]string, error) {
+	block, body, err := splitFrontMatter(content)
+	if err != nil {
+		return Entry{}, nil, err
+	}
+	if block != nil {
+		return parseFrontMatter(block)
+	}
+
+	sections, err := splitSections(body)
+	if err != nil {
+		return Entry{}, nil, err
+	}
+
+	entry := Entry{Checklist: Checklist{}}
+	selectedTypes := make(map[string]string)
+	for _, section := range sections {
+		switch section.heading {
+		case "Title":
+			entry.Title = strings.Join(section.lines, "\n")
+		case "Motivation":
+			entry.Motivation = parseParagraph(section.lines)
+		case "Description":
+			entry.Description = parseParagraph(section.lines)
+		case "Type of change":
+			entry.ChangeTypes = []string{}
+			for _, line := range section.lines {
+				name, value, err := parseChangeType(line)
+				if err != nil {
+					return Entry{}, nil, err
+				}
+				entry.ChangeTypes = append(entry.ChangeTypes, name)
+				selectedTypes[name] = value
+			}
+		case "To-do before merge":
+			entry.Todos, err = parseList(section.lines, "- [ ] ")
+		case "Changes to existing models:":
+			entry.ModelChanges, err = parseList(section.lines, "- ")
+		case "Testing Instructions":
+			entry.Testing, err = parseTestingSteps(section.lines)
+		case "Checklist":
+			entry.Checklist, err = parseChecklist(section.lines)
+		case "Commit List":
+			err = parseCommits(&entry.Metadata, section.lines)
+		case "Related tickets":
+			entry.Metadata.Tickets, err = parseTickets(section.lines)
+		case "Components":
+			entry.Components, err = parseList(section.lines, "- ")
+		case "Contributors":
+			entry.Metadata.Contributors = parseContributors(section.lines)
+		case "Files changed":
+			entry.Metadata.Files, err = parseFiles(section.lines)
+		default:
+			err = fmt.Errorf("%w: unknown section %q", InvalidEntryError, section.heading)
+		}
+		if err != nil {
+			return Entry{}, nil, err
+		}
+	}
+	return entry, selectedTypes, nil
+}
+
+type markdownSection struct {
+	heading string
+	lines   []string
+}
+
+// splitSections cuts content at "## " headings, dropping the blank lines
+// that separate a heading from its body and the body from the next heading.
+func splitSections(content string) ([]markdownSection, error) {
+	var sections []markdownSection
+	for _, line := range strings.Split(content, "\n") {
+		if heading, ok := strings.CutPrefix(line, "## "); ok {
+			sections = append(sections, markdownSection{heading: heading})
+			continue
+		}
+		if len(sections) == 0 {
+			if strings.TrimSpace(line) != "" {
+				return nil, fmt.Errorf("%w: text before the first section", InvalidEntryError)
+			}
+			continue
+		}
+		current := &sections[len(sections)-1]
+		if len(current.lines) == 0 && line == "" {
+			continue
+		}
+		current.lines = append(current.lines, line)
+	}
+	if len(sections) == 0 || sections[0].heading != "Title" {
+		return nil, fmt.Errorf("%w: missing Title section", InvalidEntryError)
+	}
+
+	for i := range sections {
+		lines := sections[i].lines
+		for len(lines) > 0 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		sections[i].lines = lines
+	}
+	return sections, nil
+}
+
+// parseParagraph undoes the hard line breaks ("  " line endings) written for
+// multi-line text.
+func parseParagraph(lines []string) string {
+	text := make([]string, len(lines))
+	for i, line := range lines {
+		text[i] = strings.TrimSuffix(line, "  ")
+	}
+	return strings.Join(text, "\n")
+}
+
+func parseChangeType(line string) (name, value string, err error) {
+	checked := strings.HasPrefix(line, "- [x] ")
+	if !checked && !strings.HasPrefix(line, "- [ ] ") {
+		return "", "", fmt.Errorf("%w: bad change type line %q", InvalidEntryError, line)
+	}
+	name = line[len("- [ ] "):]
+	if !checked {
+		return name, "", nil
+	}
+	if name, detail, ok := strings.Cut(name, ": "); ok {
+		return name, detail, nil
+	}
+	return name, name, nil
+}
+
+func parseList(lines []string, prefix string) ([]string, error) {
+	var items []string
+	for _, line := range lines {
+		item, ok := strings.CutPrefix(line, prefix)
+		if !ok {
+			return nil, fmt.Errorf("%w: bad list item %q", InvalidEntryError, line)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func parseTestingSteps(lines []string) ([]string, error) {
+	var steps []string
+	for _, line := range lines {
+		match := testingStepPattern.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf("%w: bad testing step %q", InvalidEntryError, line)
+		}
+		steps = append(steps, match[1])
+	}
+	return steps, nil
+}
+
+// parseChecklist reads checklist lines. Items whose text matches a built-in
+// item get its id back.
+func parseChecklist(lines []string) (Checklist, error) {
+	defaults := DefaultChecklist()
+	checklist := Checklist{}
+	for _, line := range lines {
+		checked := strings.HasPrefix(line, "- [x] ")
+		if !checked && !strings.HasPrefix(line, "- [ ] ") {
+			return nil, fmt.Errorf("%w: bad checklist line %q", InvalidEntryError, line)
+		}
+		item := ChecklistItem{Text: line[len("- [ ] "):], Checked: checked}
+		for _, known := range defaults {
+			if known.Text == item.Text {
+				item.ID = known.ID
+				item.Default = known.Default
+			}
+		}
+		checklist = append(checklist, item)
+	}
+	return checklist, nil
+}
+
+func parseCommits(metadata *Metadata, lines []string) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	if match := commitRangePattern.FindStringSubmatch(lines[0]); match != nil {
+		metadata.TargetBranch, metadata.Branch = match[1], match[2]
+	} else if match := commitBranchPattern.FindStringSubmatch(lines[0]); match != nil {
+		metadata.Branch = match[1]
+	} else {
+		return fmt.Errorf("%w: bad commit list header %q", InvalidEntryError, lines[0])
+	}
+
+	for _, line := range lines[1:] {
+		match := commitLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			return fmt.Errorf("%w: bad commit line %q", InvalidEntryError, line)
+		}
+		metadata.Commits = append(metadata.Commits, GitCommit{Hash: match[1], CommitUrl: match[2], Message: match[3]})
+	}
+	return nil
+}
+
+func parseTickets(lines []string) ([]Ticket, error) {
+	var tickets []Ticket
+	for _, line := range lines {
+		match := ticketLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf("%w: bad ticket line %q", InvalidEntryError, line)
+		}
+		if match[3] != "" {
+			tickets = append(tickets, Ticket{Key: match[3]})
+		} else {
+			tickets = append(tickets, Ticket{Key: match[1], URL: match[2]})
+		}
+	}
+	return tickets, nil
+}
+
+// parseContributors reads the credited names and handles back.
+func parseContributors(lines []string) []Contributor {
+	var contributors []Contributor
+	for _, mention := range strings.Split(strings.Join(lines, " "), ", ") {
+		if mention = strings.TrimSpace(mention); mention == "" {
+			continue
+		}
+		if handle, ok := strings.CutPrefix(mention, "@"); ok {
+			contributors = append(contributors, Contributor{Handle: handle})
+		} else {
+			contributors = append(contributors, Contributor{Name: mention})
+		}
+	}
+	return contributors
+}
+
+// parseFiles reads the files of the Files changed section, skipping the
+// totals and directory lines derived from them.
+func parseFiles(lines []string) ([]FileChange, error) {
+	var files []FileChange
+	for _, line := range lines {
+		if line == "" || fileSummaryPattern.MatchString(line) {
+			continue
+		}
+		match := fileLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf("%w: bad changed file line %q", InvalidEntryError, line)
+		}
+		file := FileChange{OldPath: match[1], Path: match[2], Binary: match[3] == ""}
+		file.Added, _ = strconv.Atoi(match[3])
+		file.Deleted, _ = strconv.Atoi(match[4])
+		files = append(files, file)
+	}
+	return files, nil
+}
@@ -0,0 +1,168 @@
+package changelog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParse_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name          string
+		entry         Entry
+		selectedTypes map[string]string
+	}{
+		{
+			name:          "minimal",
+			entry:         Entry{Title: "Minimal"},
+			selectedTypes: map[string]string{"Bug fix": "Bug fix"},
+		},
+		{
+			name: "every section",
+			entry: Entry{
+				Title:        "Everything",
+				Motivation:   "First line\n\nThird line after a blank one",
+				Description:  "Description\nwith two lines\n",
+				Todos:        []string{"Run migration", "Flip the flag"},
+				ModelChanges: []string{"User.email is unique"},
+				Testing:      []string{"Open the page", "Click save"},
+				Checklist:    checklistWith(ChecklistSelfReview, ChecklistReadmeUpdated),
+				Components:   []string{"api", "web"},
+				Metadata: Metadata{
+					Branch:       "feature/login",
+					TargetBranch: "main",
+					Commits: []GitCommit{
+						{Hash: "abcdef1234567", Message: "Fix (the) login", CommitUrl: "https://example.com/commit/abcdef1234567"},
+						{Hash: "1234567", Message: "Tidy up"},
+					},
+					Tickets: []Ticket{
+						{Key: "PAY-1234", URL: "https://jira.example.com/browse/PAY-1234"},
+						{Key: "#12"},
+					},
+					Contributors: []Contributor{
+						{Name: "Jane Doe", Email: "jane@example.com", Handle: "jane"},
+						{Name: "Bob Stone"},
+					},
+					Files: []FileChange{
+						{Path: "cli/login.go", Added: 12, Deleted: 3},
+						{Path: "cli/auth.go", OldPath: "cli/session.go", Added: 1, Deleted: 1},
+						{Path: "logo.png", Binary: true},
+					},
+				},
+			},
+			selectedTypes: map[string]string{"Bug fix": "Bug fix", "New feature": "", "Other": "Security"},
+		},
+		{
+			name: "custom types and checklist",
+			entry: Entry{
+				Title:       "Custom",
+				ChangeTypes: []string{"Fix", "Feature", "Other"},
+				Checklist:   Checklist{{ID: "migration", Text: "Migration ran", Checked: true}},
+				Metadata: Metadata{
+					Branch:  "feature",
+					Commits: []GitCommit{{Hash: "abc1234", Message: "Work", CommitUrl: "u/abc1234"}},
+				},
+			},
+			selectedTypes: map[string]string{"Feature": "Feature", "Other": "Other"},
+		},
+		{
+			name:  "empty checklist",
+			entry: Entry{Title: "No checklist", Checklist: Checklist{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.entry.GenerateMarkdown(tt.selectedTypes)
+			parsed, selectedTypes, err := Parse(original)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if again := parsed.GenerateMarkdown(selectedTypes); again != original {
+				t.Errorf("round trip changed the markdown:\n%s\n---\n%s", original, again)
+			}
+		})
+	}
+}
+
+func TestParse_Fields(t *testing.T) {
+	entry := Entry{
+		Title:       "Fix login",
+		Description: "Line one\nLine two",
+		Testing:     []string{"Log in"},
+		Checklist:   checklistWith(ChecklistIncludesTesting),
+		Metadata: Metadata{
+			Branch:       "fix/login",
+			TargetBranch: "develop",
+			Commits:      []GitCommit{{Hash: "abcdef1", Message: "Fix", CommitUrl: "https://example.com/abcdef1"}},
+		},
+	}
+	parsed, selectedTypes, err := Parse(entry.GenerateMarkdown(map[string]string{"Bug fix": "Bug fix", "Other": "Security"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if parsed.Title != entry.Title || parsed.Description != entry.Description {
+		t.Errorf("unexpected text fields %q, %q", parsed.Title, parsed.Description)
+	}
+	if !reflect.DeepEqual(parsed.Testing, entry.Testing) {
+		t.Errorf("unexpected testing steps %v", parsed.Testing)
+	}
+	if !reflect.DeepEqual(parsed.ChangeTypes, DefaultChangeTypes) {
+		t.Errorf("unexpected change types %v", parsed.ChangeTypes)
+	}
+	if selectedTypes["Bug fix"] != "Bug fix" || selectedTypes["Other"] != "Security" || selectedTypes["New feature"] != "" {
+		t.Errorf("unexpected selected types %v", selectedTypes)
+	}
+	if !parsed.Checklist.Checked(ChecklistIncludesTesting) || parsed.Checklist.Checked(ChecklistSelfReview) {
+		t.Errorf("expected checklist ids to be recovered, got %+v", parsed.Checklist)
+	}
+	if !reflect.DeepEqual(parsed.Metadata, entry.Metadata) {
+		t.Errorf("expected metadata %+v, got %+v", entry.Metadata, parsed.Metadata)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"empty", ""},
+		{"no title", "## Description\n\nText\n"},
+		{"text before title", "Hello\n## Title\n\nX\n"},
+		{"unknown section", "## Title\n\nX\n\n## Notes\n\nY\n"},
+		{"bad change type", "## Title\n\nX\n\n## Type of change\n\n* Bug fix\n"},
+		{"bad testing step", "## Title\n\nX\n\n## Testing Instructions\n\n- Step\n"},
+		{"bad commit header", "## Title\n\nX\n\n## Commit List\n\n- [abc](u) m\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Parse(tt.content); !errors.Is(err, InvalidEntryError) {
+				t.Errorf("expected InvalidEntryError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "1700000000_user_feature.md")
+	entry := Entry{Title: "From disk"}
+	if err := os.WriteFile(path, []byte(entry.GenerateMarkdown(nil)), 0644); err != nil {
+		t.Fatalf("failed to write entry: %v", err)
+	}
+
+	parsed, _, err := ParseFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parsed.Title != "From disk" || parsed.Filename != "1700000000_user_feature.md" {
+		t.Errorf("unexpected entry %+v", parsed)
+	}
+
+	if _, _, err := ParseFile(filepath.Join(t.TempDir(), "missing.md")); err == nil {
+		t.Error("expected error for a missing file")
+	}
+}
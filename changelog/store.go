@@ -0,0 +1,112 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultDir is where entries are saved, relative to the working directory.
+var DefaultDir = filepath.Join(".logs", ".changelog")
+
+// ArchiveDir is the directory, inside the entry directory, that released
+// entries are moved to. Each release gets its own subdirectory.
+const ArchiveDir = "released"
+
+// ListEntryFiles returns the markdown entry files in dir, oldest first.
+// A missing directory is not an error, it simply has no entries.
+func ListEntryFiles(dir string) ([]string, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".md") {
+			continue
+		}
+		names = append(names, file.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// CreatedAt returns the creation time encoded in a filename produced by
+// GenerateFilename. The second value is false when there is no timestamp.
+func CreatedAt(filename string) (time.Time, bool) {
+	prefix, _, found := strings.Cut(filepath.Base(filename), "_")
+	if !found {
+		return time.Time{}, false
+	}
+	timestamp, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(timestamp, 0), true
+}
+
+// LatestEntryForBranch returns the most recent entry file in dir whose
+// branch is branch, or "" when there is none. Released entries in
+// ArchiveDir are searched when no unreleased one matches, and returned
+// relative to dir. Entries that cannot be read are skipped.
+func LatestEntryForBranch(dir, branch string) (string, error) {
+	files, err := ListEntryFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	if file := latestOnBranch(dir, files, branch); file != "" {
+		return file, nil
+	}
+	archived, err := listArchivedEntryFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	return latestOnBranch(dir, archived, branch), nil
+}
+
+// latestOnBranch returns the last of files, relative to dir, written on
+// branch.
+func latestOnBranch(dir string, files []string, branch string) string {
+	for i := len(files) - 1; i >= 0; i-- {
+		entry, _, err := ParseFile(filepath.Join(dir, files[i]))
+		if err == nil && entry.Metadata.Branch == branch {
+			return files[i]
+		}
+	}
+	return ""
+}
+
+// listArchivedEntryFiles returns the entry files of every release in the
+// archive of dir, relative to dir and oldest first.
+func listArchivedEntryFiles(dir string) ([]string, error) {
+	releases, err := os.ReadDir(filepath.Join(dir, ArchiveDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var files []string
+	for _, release := range releases {
+		if !release.IsDir() {
+			continue
+		}
+		names, err := ListEntryFiles(filepath.Join(dir, ArchiveDir, release.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			files = append(files, filepath.Join(ArchiveDir, release.Name(), name))
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool { return filepath.Base(files[i]) < filepath.Base(files[j]) })
+	return files, nil
+}
@@ -0,0 +1,108 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListEntryFiles(t *testing.T) {
+	t.Run("missing directory has no entries", func(t *testing.T) {
+		files, err := ListEntryFiles(filepath.Join(t.TempDir(), "missing"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(files) != 0 {
+			t.Errorf("expected no entries, got %v", files)
+		}
+	})
+
+	t.Run("returns markdown files sorted", func(t *testing.T) {
+		dir := t.TempDir()
+		for _, name := range []string{"1700000200_b_x.md", "1700000100_a_y.md", "readme.txt"} {
+			if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+				t.Fatalf("failed to write file: %v", err)
+			}
+		}
+		if err := os.Mkdir(filepath.Join(dir, "archive.md"), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+
+		files, err := ListEntryFiles(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{"1700000100_a_y.md", "1700000200_b_x.md"}
+		if len(files) != len(want) {
+			t.Fatalf("expected %v, got %v", want, files)
+		}
+		for i := range want {
+			if files[i] != want[i] {
+				t.Errorf("expected %q at index %d, got %q", want[i], i, files[i])
+			}
+		}
+	})
+}
+
+func TestCreatedAt(t *testing.T) {
+	created, ok := CreatedAt("1700000000_user_main.md")
+	if !ok {
+		t.Fatal("expected timestamp to be found")
+	}
+	if created.Unix() != 1700000000 {
+		t.Errorf("expected 1700000000, got %d", created.Unix())
+	}
+
+	if _, ok := CreatedAt("notes.md"); ok {
+		t.Error("expected no timestamp for notes.md")
+	}
+	if _, ok := CreatedAt("abc_user_main.md"); ok {
+		t.Error("expected no timestamp for non-numeric prefix")
+	}
+}
+
+func TestLatestEntryForBranch(t *testing.T) {
+	dir := t.TempDir()
+	entries := map[string]string{
+		"1700000100_user_feature-login.md":                "feature/login",
+		"1700000200_user_feature-login.md":                "feature/login",
+		"1700000300_user_main.md":                         "main",
+		"1700000400_user_feature-login.md":                "feature-login",
+		"released/1.0.0/1700000050_user_fix-crash.md":     "fix/crash",
+		"released/1.1.0/1700000060_user_fix-crash.md":     "fix/crash",
+		"released/1.1.0/1700000000_user_feature-login.md": "feature/login",
+	}
+	for name, branch := range entries {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		content := "## Title\n\nEntry\n\n## Commit List\n\nCommits from branch '" + branch + "':\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "1700000500_user_broken.md"), []byte("## Unknown\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		branch string
+		want   string
+	}{
+		{"feature/login", "1700000200_user_feature-login.md"},
+		{"feature-login", "1700000400_user_feature-login.md"},
+		{"main", "1700000300_user_main.md"},
+		{"fix/crash", filepath.Join("released", "1.1.0", "1700000060_user_fix-crash.md")},
+		{"develop", ""},
+	}
+	for _, tt := range tests {
+		got, err := LatestEntryForBranch(dir, tt.branch)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("LatestEntryForBranch(%q) = %q, want %q", tt.branch, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package changelog
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Template names, also the keys of a repository's template overrides.
+const (
+	TemplateMarkdown = "markdown"
+	TemplatePR       = "pr"
+)
+
+// TemplateNames lists every template an entry is rendered with.
+var TemplateNames = []string{TemplateMarkdown, TemplatePR}
+
+// Predefined errors
+var (
+	UnknownTemplateError = errors.New("unknown template")
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+var defaultTemplates = template.Must(parseBuiltinTemplates())
+
+// ChangeType is one change type option as seen by templates.
+type ChangeType struct {
+	Name string
+	// Detail is the custom text given for "Other".
+	Detail   string
+	Selected bool
+}
+
+func (c ChangeType) String() string {
+	if c.Detail != "" {
+		return c.Name + ": " + c.Detail
+	}
+	return c.Name
+}
+
+// TemplateData is what templates are executed with.
+type TemplateData struct {
+	Entry     *Entry
+	Types     []ChangeType
+	Checklist Checklist
+	Commits   []GitCommit
+	Files     ChangedFiles
+}
+
+// Templates is a set of named templates entries are rendered with.
+type Templates struct {
+	set *template.Template
+}
+
+// TemplateFuncs are the helpers available to every template:
+//
+//	checkbox  "x" for true, " " for false
+//	join      strings.Join
+//	shortHash the first seven characters of a commit hash
+//	indent    prefixes every line with n spaces
+//	lines     splits text into lines
+//	trim      strings.TrimSpace
+//	inc       adds one, for numbered lists
+//	date      formats a commit date as 2006-01-02, empty when unknown
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"checkbox":  checkboxValue,
+		"join":      strings.Join,
+		"shortHash": shortHash,
+		"indent":    indent,
+		"lines":     func(text string) []string { return strings.Split(text, "\n") },
+		"trim":      strings.TrimSpace,
+		"inc":       func(i int) int { return i + 1 },
+		"date":      formatDate,
+	}
+}
+
+func parseBuiltinTemplates() (*template.Template, error) {
+	set := template.New("").Funcs(TemplateFuncs())
+	for _, name := range TemplateNames {
+		content, err := builtinTemplates.ReadFile("templates/" + name + ".tmpl")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := set.New(name).Parse(string(content)); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// DefaultTemplates returns the built-in templates.
+func DefaultTemplates() *Templates {
+	return &Templates{set: defaultTemplates}
+}
+
+// LoadTemplates returns the built-in templates with the ones named in
+// overrides replaced by the contents of the mapped files.
+func LoadTemplates(overrides map[string]string) (*Templates, error) {
+	if len(overrides) == 0 {
+		return DefaultTemplates(), nil
+	}
+	set, err := defaultTemplates.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if set.Lookup(name) == nil {
+			return nil, fmt.Errorf("%w %q", UnknownTemplateError, name)
+		}
+		content, err := os.ReadFile(overrides[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s template: %w", name, err)
+		}
+		if _, err := set.New(name).Parse(string(content)); err != nil {
+			return nil, fmt.Errorf("failed to parse %s template: %w", name, err)
+		}
+	}
+	return &Templates{set: set}, nil
+}
+
+// Render executes the named template for entry.
+func (t *Templates) Render(name string, entry *Entry, selectedTypes map[string]string) (string, error) {
+	tmpl := t.set.Lookup(name)
+	if tmpl == nil {
+		return "", fmt.Errorf("%w %q", UnknownTemplateError, name)
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, entry.templateData(selectedTypes)); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+func (e *Entry) templateData(selectedTypes map[string]string) TemplateData {
+	return TemplateData{
+		Entry:     e,
+		Types:     e.ChangeTypeOptions(selectedTypes),
+		Checklist: e.checklist(),
+		Commits:   e.Metadata.Commits,
+		Files:     groupFiles(e.Metadata.Files, e.FileList.CollapseAfter),
+	}
+}
+
+// ChangeTypeOptions returns every change type the entry was asked with,
+// marking the ones in selectedTypes.
+func (e *Entry) ChangeTypeOptions(selectedTypes map[string]string) []ChangeType {
+	var types []ChangeType
+	for _, name := range e.changeTypes() {
+		changeType := ChangeType{Name: name}
+		if val, exists := selectedTypes[name]; exists && val != "" {
+			changeType.Selected = true
+			if name == "Other" && val != name {
+				changeType.Detail = val
+			}
+		}
+		types = append(types, changeType)
+	}
+	return types
+}
+
+func checkboxValue(checked bool) string {
+	if checked {
+		return "x"
+	}
+	return " "
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+func indent(spaces int, text string) string {
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.ReplaceAll(text, "\n", "\n"+pad)
+}
@@ -0,0 +1,122 @@
+package changelog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemplate(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "custom.tmpl")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	return path
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"checkbox", `{{checkbox true}}{{checkbox false}}`, "x "},
+		{"join", `{{join .Entry.Todos ", "}}`, "a, b"},
+		{"shortHash", `{{shortHash "0123456789"}} {{shortHash "abc"}}`, "0123456 abc"},
+		{"indent", `{{indent 2 "a\nb"}}`, "  a\n  b"},
+		{"lines", `{{range lines "a\nb"}}[{{.}}]{{end}}`, "[a][b]"},
+		{"trim", `{{trim "  a "}}`, "a"},
+		{"inc", `{{inc 1}}`, "2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates, err := LoadTemplates(map[string]string{TemplateMarkdown: writeTemplate(t, tt.template)})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			entry := &Entry{Todos: []string{"a", "b"}}
+			got, err := templates.Render(TemplateMarkdown, entry, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadTemplates_Override(t *testing.T) {
+	path := writeTemplate(t, "# {{.Entry.Title}}\n{{range .Types}}{{if .Selected}}* {{.}}\n{{end}}{{end}}")
+	templates, err := LoadTemplates(map[string]string{TemplateMarkdown: path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entry := &Entry{Title: "Custom", Templates: templates}
+	selectedTypes := map[string]string{"Bug fix": "Bug fix", "Other": "Security"}
+	if got := entry.GenerateMarkdown(selectedTypes); got != "# Custom\n* Bug fix\n* Other: Security\n" {
+		t.Errorf("unexpected markdown %q", got)
+	}
+	if got := entry.GenerateBitbucketPR(selectedTypes); !strings.HasPrefix(got, "### Custom\n") {
+		t.Errorf("expected the built-in PR template to be kept, got %q", got)
+	}
+	if DefaultTemplates().set.Lookup(TemplateMarkdown) == templates.set.Lookup(TemplateMarkdown) {
+		t.Error("expected overrides not to touch the built-in templates")
+	}
+}
+
+func TestLoadTemplates_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]string
+		want      string
+	}{
+		{"unknown name", map[string]string{"html": writeTemplate(t, "")}, `unknown template "html"`},
+		{"missing file", map[string]string{TemplatePR: filepath.Join(t.TempDir(), "missing.tmpl")}, "failed to read pr template"},
+		{"bad syntax", map[string]string{TemplatePR: writeTemplate(t, "{{if}}")}, "failed to parse pr template"},
+		{"unknown helper", map[string]string{TemplatePR: writeTemplate(t, "{{upper .Entry.Title}}")}, "failed to parse pr template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadTemplates(tt.overrides)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestEntry_Render_Errors(t *testing.T) {
+	entry := &Entry{}
+	if _, err := entry.Render("html", nil); !errors.Is(err, UnknownTemplateError) {
+		t.Errorf("expected UnknownTemplateError, got %v", err)
+	}
+
+	templates, err := LoadTemplates(map[string]string{TemplateMarkdown: writeTemplate(t, "{{.Entry.Missing}}")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entry.Templates = templates
+	entry.Filename = "entry.md"
+	if err := entry.SaveToFile(nil, t.TempDir()); err == nil {
+		t.Error("expected SaveToFile to report template errors")
+	}
+	builtin := &Entry{Title: "Fallback"}
+	entry.Title = "Fallback"
+	if got, want := entry.GenerateMarkdown(nil), builtin.GenerateMarkdown(nil); got != want || got == "" {
+		t.Errorf("expected the built-in markdown when the template fails, got %q", got)
+	}
+}
+
+func TestEntry_GenerateMarkdown_ShortHashes(t *testing.T) {
+	entry := &Entry{Metadata: Metadata{Branch: "feature", Commits: []GitCommit{{Hash: "abc", Message: "short"}}}}
+	if !strings.Contains(entry.GenerateMarkdown(nil), "- [abc]() short\n") {
+		t.Error("expected short hashes to render without panicking")
+	}
+}
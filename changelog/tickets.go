@@ -0,0 +1,116 @@
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Ticket is an issue tracker key the entry relates to.
+type Ticket struct {
+	Key string `json:"key" yaml:"key"`
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+// Ticket types with a built-in pattern.
+const (
+	TicketJira   = "jira"
+	TicketLinear = "linear"
+	TicketGitHub = "github"
+)
+
+// TicketTypes maps ticket types to the pattern their keys follow.
+var TicketTypes = map[string]string{
+	TicketJira:   `\b[A-Z][A-Z0-9_]+-[1-9][0-9]*\b`,
+	TicketLinear: `\b[A-Z][A-Z0-9]+-[1-9][0-9]*\b`,
+	TicketGitHub: `(?:^|[^\w&/])#([1-9][0-9]*)\b`,
+}
+
+// Predefined errors
+var (
+	InvalidTicketPatternError = errors.New("invalid ticket pattern")
+)
+
+// TicketPattern finds ticket keys in branch names and commit messages and
+// links them.
+type TicketPattern struct {
+	pattern *regexp.Regexp
+	url     string
+}
+
+// NewTicketPattern compiles pattern, a regular expression matching ticket
+// keys. url is a link template where {key} is the whole key and {id} the
+// pattern's first group, or the whole key without one; empty leaves keys
+// unlinked. For patterns that match surrounding text, such as the "#" of
+// GitHub issues, the key is taken from the first "#" or word character on.
+func NewTicketPattern(pattern, url string) (TicketPattern, error) {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return TicketPattern{}, fmt.Errorf("%w %q: %v", InvalidTicketPatternError, pattern, err)
+	}
+	return TicketPattern{pattern: compiled, url: url}, nil
+}
+
+// find returns the tickets mentioned in text, in order.
+func (p TicketPattern) find(text string) []Ticket {
+	var tickets []Ticket
+	for _, match := range p.pattern.FindAllStringSubmatch(text, -1) {
+		key := strings.TrimLeftFunc(match[0], func(r rune) bool {
+			return r != '#' && !isWordRune(r)
+		})
+		id := key
+		if len(match) > 1 && match[1] != "" {
+			id = match[1]
+		}
+		ticket := Ticket{Key: key}
+		if p.url != "" {
+			ticket.URL = strings.NewReplacer("{key}", key, "{id}", id).Replace(p.url)
+		}
+		tickets = append(tickets, ticket)
+	}
+	return tickets
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+// ExtractTickets finds the tickets mentioned in the branch name and the
+// commits' subjects, bodies and trailers, each once in the order first
+// seen. The first pattern to match a key links it.
+func ExtractTickets(patterns []TicketPattern, branch string, commits []GitCommit) []Ticket {
+	texts := []string{branch}
+	for _, commit := range commits {
+		texts = append(texts, commit.Message, commit.Body)
+		for _, trailer := range commit.Trailers {
+			texts = append(texts, trailer.Value)
+		}
+	}
+
+	var tickets []Ticket
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, pattern := range patterns {
+			for _, ticket := range pattern.find(text) {
+				if !seen[ticket.Key] {
+					seen[ticket.Key] = true
+					tickets = append(tickets, ticket)
+				}
+			}
+		}
+	}
+	return tickets
+}
+
+// MatchTicket reports whether key matches one of patterns, path.Match
+// globs compared case-insensitively.
+func MatchTicket(key string, patterns ...string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToUpper(pattern), strings.ToUpper(key)); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package changelog
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func mustTicketPattern(t *testing.T, pattern, url string) TicketPattern {
+	t.Helper()
+	compiled, err := NewTicketPattern(pattern, url)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return compiled
+}
+
+func TestExtractTickets(t *testing.T) {
+	patterns := []TicketPattern{
+		mustTicketPattern(t, TicketTypes[TicketJira], "https://jira.example.com/browse/{key}"),
+		mustTicketPattern(t, TicketTypes[TicketGitHub], "https://github.com/org/repo/issues/{id}"),
+	}
+	commits := []GitCommit{
+		{Message: "feat: refund flow (#12)", Body: "Follows up on PAY-1234 and UTF-8 handling, see https://x.test/#3 and &#39;"},
+		{Message: "fix: rounding", Trailers: []Trailer{{Key: "Refs", Value: "PAY-77, #40"}}},
+	}
+
+	tickets := ExtractTickets(patterns, "feature/PAY-1234-refund-flow", commits)
+	expected := []Ticket{
+		{Key: "PAY-1234", URL: "https://jira.example.com/browse/PAY-1234"},
+		{Key: "#12", URL: "https://github.com/org/repo/issues/12"},
+		{Key: "UTF-8", URL: "https://jira.example.com/browse/UTF-8"},
+		{Key: "PAY-77", URL: "https://jira.example.com/browse/PAY-77"},
+		{Key: "#40", URL: "https://github.com/org/repo/issues/40"},
+	}
+	if !reflect.DeepEqual(tickets, expected) {
+		t.Errorf("expected %+v, got %+v", expected, tickets)
+	}
+
+	linear := []TicketPattern{mustTicketPattern(t, `\bENG-\d+\b`, "")}
+	if tickets := ExtractTickets(linear, "eng-12", []GitCommit{{Message: "ENG-5 fix"}}); !reflect.DeepEqual(tickets, []Ticket{{Key: "ENG-5"}}) {
+		t.Errorf("expected an unlinked ENG-5, got %+v", tickets)
+	}
+	if tickets := ExtractTickets(nil, "feature/PAY-1", nil); tickets != nil {
+		t.Errorf("expected no tickets without patterns, got %+v", tickets)
+	}
+}
+
+func TestNewTicketPattern_Invalid(t *testing.T) {
+	if _, err := NewTicketPattern(`PAY-(\d+`, ""); !errors.Is(err, InvalidTicketPatternError) {
+		t.Errorf("expected InvalidTicketPatternError, got %v", err)
+	}
+}
+
+func TestMatchTicket(t *testing.T) {
+	tests := []struct {
+		key      string
+		patterns []string
+		want     bool
+	}{
+		{"PAY-1234", []string{"PAY-*"}, true},
+		{"PAY-1234", []string{"pay-1234"}, true},
+		{"#12", []string{"OPS-*", "#12"}, true},
+		{"PAY-1234", []string{"OPS-*"}, false},
+		{"PAY-1234", nil, false},
+	}
+	for _, tt := range tests {
+		if got := MatchTicket(tt.key, tt.patterns...); got != tt.want {
+			t.Errorf("MatchTicket(%q, %q) = %v, want %v", tt.key, tt.patterns, got, tt.want)
+		}
+	}
+}
+
+func TestEntry_Render_Tickets(t *testing.T) {
+	entry := Entry{
+		Title: "Refunds",
+		Metadata: Metadata{Tickets: []Ticket{
+			{Key: "PAY-1", URL: "https://jira.example.com/browse/PAY-1"},
+			{Key: "#2"},
+		}},
+	}
+
+	pr := entry.GenerateBitbucketPR(nil)
+	if !strings.Contains(pr, "**Related tickets:** [PAY-1](https://jira.example.com/browse/PAY-1), #2\n") {
+		t.Errorf("expected related tickets in PR text, got\n%s", pr)
+	}
+	markdown := entry.GenerateMarkdown(nil)
+	if !strings.Contains(markdown, "## Related tickets\n\n- [PAY-1](https://jira.example.com/browse/PAY-1)\n- #2\n\n## Type of change") {
+		t.Errorf("expected a Related tickets section, got\n%s", markdown)
+	}
+}
@@ -0,0 +1,272 @@
+// Package check validates the changelog entry of a branch, for CI to fail
+// pull requests whose entry is missing, incomplete or out of date.
+package check
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/abirhasanmubin/changelog-go/changelog"
+)
+
+// Names of the checks, in the order they run.
+const (
+	CheckEntry     = "entry"
+	CheckChecklist = "checklist"
+	CheckTesting   = "testing"
+	CheckCommits   = "commits"
+)
+
+// Status is the outcome of one check.
+type Status string
+
+const (
+	Pass Status = "pass"
+	Fail Status = "fail"
+	// Skip is a check that could not run, such as every check after a
+	// missing entry.
+	Skip Status = "skip"
+)
+
+// Result is the outcome of one check with what it found.
+type Result struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+}
+
+// Report is the outcome of every check for a branch.
+type Report struct {
+	Branch string `json:"branch"`
+	Target string `json:"target,omitempty"`
+	// File is the entry checked, empty when there is none.
+	File    string   `json:"file,omitempty"`
+	Passed  bool     `json:"passed"`
+	Results []Result `json:"checks"`
+}
+
+// Options describe what to check.
+type Options struct {
+	// EntryDir holds the entries; the branch's most recent one is checked.
+	EntryDir string
+	// Root, when set, is what the paths in the report are relative to,
+	// such as the repository root for CI annotations.
+	Root   string
+	Branch string
+	// Target is the branch the entry's branch merges into. Empty uses the
+	// entry's target branch.
+	Target string
+	// Checklist is the configured checklist, whose required_for patterns
+	// win over those saved in the entry.
+	Checklist changelog.Checklist
+	// TestingChangeTypes are the change types that need testing
+	// instructions. Nil uses DefaultTestingChangeTypes.
+	TestingChangeTypes []string
+	// ListCommits lists the commits on the branch since target, such as
+	// changelog.BranchCommits, leaving out those that only add or update
+	// entries. Nil skips the commits check.
+	ListCommits func(target string) ([]changelog.GitCommit, error)
+}
+
+// DefaultTestingChangeTypes are the change types that need testing
+// instructions unless configured otherwise.
+var DefaultTestingChangeTypes = []string{"New feature"}
+
+// Run checks the branch's entry. Failed checks are reported, not returned
+// as errors; errors are for entries that cannot be read.
+func Run(opts Options) (Report, error) {
+	report := Report{Branch: opts.Branch, Target: opts.Target}
+	file, err := changelog.LatestEntryForBranch(opts.EntryDir, opts.Branch)
+	if err != nil {
+		return report, err
+	}
+	if file == "" {
+		report.add(CheckEntry, Fail, fmt.Sprintf("no entry for branch '%s' in %s; run 'changelog-go new' to write one", opts.Branch, relative(opts.Root, opts.EntryDir)))
+		for _, name := range []string{CheckChecklist, CheckTesting, CheckCommits} {
+			report.add(name, Skip, "no entry")
+		}
+		return report.finish(), nil
+	}
+
+	path := filepath.Join(opts.EntryDir, file)
+	entry, selectedTypes, err := changelog.ParseFile(path)
+	if err != nil {
+		return report, err
+	}
+	report.File = relative(opts.Root, path)
+	if report.Target == "" {
+		report.Target = entry.Metadata.TargetBranch
+	}
+	report.add(CheckEntry, Pass, report.File)
+	report.checkChecklist(entry, opts.Checklist)
+	testingTypes := opts.TestingChangeTypes
+	if testingTypes == nil {
+		testingTypes = DefaultTestingChangeTypes
+	}
+	report.checkTesting(entry, selectedTypes, testingTypes)
+
+	switch {
+	case report.Target == "":
+		report.add(CheckCommits, Skip, "no target branch; pass --target")
+	case opts.ListCommits == nil:
+		report.add(CheckCommits, Skip, "commits not listed")
+	default:
+		if commits, err := opts.ListCommits(report.Target); err != nil {
+			report.add(CheckCommits, Skip, fmt.Sprintf("cannot list the commits since '%s': %v", report.Target, err))
+		} else {
+			report.checkCommits(entry, commits)
+		}
+	}
+	return report.finish(), nil
+}
+
+func relative(root, path string) string {
+	if root == "" {
+		return path
+	}
+	if rel, err := filepath.Rel(root, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+func (r *Report) add(name string, status Status, message string) {
+	r.Results = append(r.Results, Result{Name: name, Status: status, Message: message})
+}
+
+func (r Report) finish() Report {
+	r.Passed = true
+	for _, result := range r.Results {
+		if result.Status == Fail {
+			r.Passed = false
+		}
+	}
+	return r
+}
+
+// Failures returns the failed checks.
+func (r Report) Failures() []Result {
+	var failures []Result
+	for _, result := range r.Results {
+		if result.Status == Fail {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// checkChecklist fails on items required for the target branch that the
+// entry left unchecked. Items are matched by id, then by text.
+func (r *Report) checkChecklist(entry changelog.Entry, configured changelog.Checklist) {
+	required := configured
+	if required == nil {
+		required = entry.Checklist
+	}
+	var missing []string
+	for _, item := range required {
+		if !item.IsRequiredFor(r.Target) {
+			continue
+		}
+		if answered := findItem(entry.Checklist, item); answered == nil || !answered.Checked {
+			missing = append(missing, item.Text)
+		}
+	}
+	if len(missing) > 0 {
+		r.add(CheckChecklist, Fail, fmt.Sprintf("required for '%s' but unchecked: %s", r.Target, strings.Join(missing, "; ")))
+		return
+	}
+	r.add(CheckChecklist, Pass, "required items checked")
+}
+
+func findItem(checklist changelog.Checklist, item changelog.ChecklistItem) *changelog.ChecklistItem {
+	if item.ID != "" {
+		if found := checklist.Item(item.ID); found != nil {
+			return found
+		}
+	}
+	for i := range checklist {
+		if checklist[i].Text == item.Text {
+			return &checklist[i]
+		}
+	}
+	return nil
+}
+
+// checkTesting fails on entries of a change type in testingTypes without
+// testing steps.
+func (r *Report) checkTesting(entry changelog.Entry, selectedTypes map[string]string, testingTypes []string) {
+	var selected []string
+	for _, changeType := range testingTypes {
+		if selectedTypes[changeType] != "" {
+			selected = append(selected, changeType)
+		}
+	}
+	if len(selected) == 0 {
+		if len(testingTypes) == 0 {
+			r.add(CheckTesting, Pass, "no change type needs testing instructions")
+		} else {
+			r.add(CheckTesting, Pass, "not a "+strings.Join(testingTypes, " or "))
+		}
+		return
+	}
+	if len(entry.Testing) == 0 {
+		r.add(CheckTesting, Fail, strings.Join(selected, " and ")+" without testing instructions")
+		return
+	}
+	r.add(CheckTesting, Pass, fmt.Sprintf("%d testing step%s", len(entry.Testing), plural(len(entry.Testing))))
+}
+
+// checkCommits fails when the entry's commit list and the branch's commits
+// differ, as after new commits or a rebase. Hashes are compared by prefix,
+// since entries read from markdown keep only the short hash.
+func (r *Report) checkCommits(entry changelog.Entry, commits []changelog.GitCommit) {
+	var unlisted, gone []string
+	for _, commit := range commits {
+		if !containsCommit(entry.Metadata.Commits, commit.Hash) {
+			unlisted = append(unlisted, describe(commit))
+		}
+	}
+	for _, listed := range entry.Metadata.Commits {
+		if !containsCommit(commits, listed.Hash) {
+			gone = append(gone, describe(listed))
+		}
+	}
+
+	var problems []string
+	if len(unlisted) > 0 {
+		problems = append(problems, fmt.Sprintf("%d commit%s missing from the entry: %s", len(unlisted), plural(len(unlisted)), strings.Join(unlisted, ", ")))
+	}
+	if len(gone) > 0 {
+		problems = append(problems, fmt.Sprintf("%d listed commit%s no longer on the branch: %s", len(gone), plural(len(gone)), strings.Join(gone, ", ")))
+	}
+	if len(problems) > 0 {
+		r.add(CheckCommits, Fail, fmt.Sprintf("%s; run 'changelog-go edit %s' to refresh", strings.Join(problems, "; "), r.Branch))
+		return
+	}
+	r.add(CheckCommits, Pass, fmt.Sprintf("%d commit%s since '%s'", len(commits), plural(len(commits)), r.Target))
+}
+
+func containsCommit(commits []changelog.GitCommit, hash string) bool {
+	for _, commit := range commits {
+		if hash != "" && commit.Hash != "" && (strings.HasPrefix(commit.Hash, hash) || strings.HasPrefix(hash, commit.Hash)) {
+			return true
+		}
+	}
+	return false
+}
+
+func describe(commit changelog.GitCommit) string {
+	hash := commit.Hash
+	if len(hash) > 7 {
+		hash = hash[:7]
+	}
+	return strings.TrimSpace(hash + " " + commit.Message)
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
@@ -0,0 +1,260 @@
+package check
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abirhasanmubin/changelog-go/changelog"
+)
+
+func writeEntry(t *testing.T, dir, name string, entry changelog.Entry, selectedTypes map[string]string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	content, err := entry.GenerateFile(selectedTypes)
+	if err != nil {
+		t.Fatalf("failed to render entry: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write entry: %v", err)
+	}
+}
+
+func listing(commits ...changelog.GitCommit) func(string) ([]changelog.GitCommit, error) {
+	return func(string) ([]changelog.GitCommit, error) { return commits, nil }
+}
+
+func statuses(report Report) string {
+	var parts []string
+	for _, result := range report.Results {
+		parts = append(parts, result.Name+"="+string(result.Status))
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestRun(t *testing.T) {
+	checklist := changelog.Checklist{
+		{ID: "self-review", Text: "Self-reviewed"},
+		{ID: "migration", Text: "Migration ran on staging", RequiredFor: []string{"main", "release/*"}},
+	}
+	first := changelog.GitCommit{Hash: "aaaaaaa1111111111111111111111111111111111", Message: "feat: export"}
+	second := changelog.GitCommit{Hash: "bbbbbbb2222222222222222222222222222222222", Message: "fix: totals"}
+	complete := changelog.Entry{
+		Title:     "CSV export",
+		Testing:   []string{"Export a report"},
+		Checklist: changelog.Checklist{{ID: "self-review", Text: "Self-reviewed", Checked: true}, {ID: "migration", Text: "Migration ran on staging", Checked: true}},
+		Metadata: changelog.Metadata{
+			Branch:       "feature/export",
+			TargetBranch: "develop",
+			Commits:      []changelog.GitCommit{first, second},
+		},
+	}
+	feature := map[string]string{"New feature": "New feature"}
+
+	tests := []struct {
+		name         string
+		entry        *changelog.Entry
+		target       string
+		commits      []changelog.GitCommit
+		want         string
+		wantTarget   string
+		wantMessages []string
+	}{
+		{
+			name:       "complete",
+			entry:      &complete,
+			target:     "main",
+			commits:    []changelog.GitCommit{first, second},
+			want:       "entry=pass checklist=pass testing=pass commits=pass",
+			wantTarget: "main",
+		},
+		{
+			name:       "target from the entry",
+			entry:      &complete,
+			commits:    []changelog.GitCommit{first, {Hash: "bbbbbbb"}},
+			want:       "entry=pass checklist=pass testing=pass commits=pass",
+			wantTarget: "develop",
+		},
+		{
+			name: "missing entry",
+			want: "entry=fail checklist=skip testing=skip commits=skip",
+			wantMessages: []string{
+				"no entry for branch 'feature/export'",
+			},
+		},
+		{
+			name: "unchecked required item",
+			entry: func() *changelog.Entry {
+				entry := complete
+				entry.Checklist = changelog.Checklist{{ID: "migration", Text: "Migration ran on staging"}}
+				return &entry
+			}(),
+			target:       "release/2.0",
+			commits:      []changelog.GitCommit{first, second},
+			want:         "entry=pass checklist=fail testing=pass commits=pass",
+			wantMessages: []string{"required for 'release/2.0' but unchecked: Migration ran on staging"},
+		},
+		{
+			name: "feature without testing steps",
+			entry: func() *changelog.Entry {
+				entry := complete
+				entry.Testing = nil
+				return &entry
+			}(),
+			commits:      []changelog.GitCommit{first, second},
+			want:         "entry=pass checklist=pass testing=fail commits=pass",
+			wantMessages: []string{"New feature without testing instructions"},
+		},
+		{
+			name:    "rebased branch",
+			entry:   &complete,
+			target:  "main",
+			commits: []changelog.GitCommit{{Hash: "ccccccc3333", Message: "feat: export"}, second},
+			want:    "entry=pass checklist=pass testing=pass commits=fail",
+			wantMessages: []string{
+				"1 commit missing from the entry: ccccccc feat: export",
+				"1 listed commit no longer on the branch: aaaaaaa feat: export",
+				"run 'changelog-go edit feature/export'",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.entry != nil {
+				writeEntry(t, dir, "1700000000_user_feature-export.md", *tt.entry, feature)
+			}
+			report, err := Run(Options{
+				EntryDir:    dir,
+				Branch:      "feature/export",
+				Target:      tt.target,
+				Checklist:   checklist,
+				ListCommits: listing(tt.commits...),
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := statuses(report); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+			if report.Passed != !strings.Contains(tt.want, "fail") {
+				t.Errorf("unexpected passed %v", report.Passed)
+			}
+			if tt.wantTarget != "" && report.Target != tt.wantTarget {
+				t.Errorf("expected target %q, got %q", tt.wantTarget, report.Target)
+			}
+			var messages []string
+			for _, failure := range report.Failures() {
+				messages = append(messages, failure.Message)
+			}
+			for _, want := range tt.wantMessages {
+				if !strings.Contains(strings.Join(messages, "\n"), want) {
+					t.Errorf("expected %q in %q", want, messages)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_TestingChangeTypes(t *testing.T) {
+	dir := t.TempDir()
+	entry := changelog.Entry{Title: "Retry payments", Metadata: changelog.Metadata{Branch: "feature"}}
+	writeEntry(t, dir, "1700000000_user_feature.md", entry, map[string]string{"Bug fix": "Bug fix"})
+
+	tests := []struct {
+		name    string
+		types   []string
+		status  Status
+		message string
+	}{
+		{"default", nil, Pass, "not a New feature"},
+		{"configured", []string{"New feature", "Bug fix"}, Fail, "Bug fix without testing instructions"},
+		{"none", []string{}, Pass, "no change type needs testing instructions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := Run(Options{EntryDir: dir, Branch: "feature", TestingChangeTypes: tt.types})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result := report.Results[2]; result.Status != tt.status || result.Message != tt.message {
+				t.Errorf("expected %s %q, got %+v", tt.status, tt.message, result)
+			}
+		})
+	}
+}
+
+func TestRun_CommitsSkipped(t *testing.T) {
+	dir := t.TempDir()
+	writeEntry(t, dir, "1700000000_user_feature.md", changelog.Entry{Title: "No target", Metadata: changelog.Metadata{Branch: "feature"}}, nil)
+
+	report, err := Run(Options{EntryDir: dir, Branch: "feature", ListCommits: listing()})
+	if err != nil || !report.Passed || report.Results[3].Status != Skip {
+		t.Errorf("expected the commits check to be skipped without a target, got %+v, %v", report, err)
+	}
+
+	report, _ = Run(Options{EntryDir: dir, Branch: "feature", Target: "main", ListCommits: func(string) ([]changelog.GitCommit, error) {
+		return nil, errors.New("unknown revision")
+	}})
+	if result := report.Results[3]; result.Status != Skip || !strings.Contains(result.Message, "unknown revision") {
+		t.Errorf("expected the commits check to be skipped when git fails, got %+v", result)
+	}
+}
+
+func TestReport_Write(t *testing.T) {
+	report := Report{
+		Branch: "feature/export",
+		Target: "main",
+		File:   ".logs/.changelog/1700000000_user_feature-export.md",
+		Results: []Result{
+			{Name: CheckEntry, Status: Pass, Message: "found"},
+			{Name: CheckTesting, Status: Fail, Message: "New feature without testing instructions"},
+			{Name: CheckCommits, Status: Skip, Message: "no target branch"},
+		},
+	}
+
+	var text bytes.Buffer
+	if err := report.Write(&text, FormatText); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantText := "Changelog entry for 'feature/export' into 'main':\n✓ entry: found\n✗ testing: New feature without testing instructions\n- commits: no target branch\n"
+	if text.String() != wantText {
+		t.Errorf("expected\n%s\ngot\n%s", wantText, text.String())
+	}
+
+	var encoded bytes.Buffer
+	if err := report.Write(&encoded, FormatJSON); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(encoded.Bytes(), &decoded); err != nil || len(decoded.Results) != 3 || decoded.Results[1].Status != Fail {
+		t.Errorf("unexpected JSON %s: %v", encoded.String(), err)
+	}
+
+	var junit bytes.Buffer
+	if err := report.Write(&junit, FormatJUnit); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var suites junitSuites
+	if err := xml.Unmarshal(junit.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid JUnit XML: %v\n%s", err, junit.String())
+	}
+	if suites.Tests != 3 || suites.Failures != 1 || suites.Skipped != 1 || len(suites.Suites[0].Cases) != 3 {
+		t.Errorf("unexpected JUnit report:\n%s", junit.String())
+	}
+	if failure := suites.Suites[0].Cases[1].Failure; failure == nil || failure.Message != "New feature without testing instructions" {
+		t.Errorf("expected the testing case to fail, got %+v", suites.Suites[0].Cases[1])
+	}
+
+	if err := report.Write(&text, "sarif"); !errors.Is(err, UnknownFormatError) {
+		t.Errorf("expected UnknownFormatError, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package check
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Report formats.
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+)
+
+// Formats lists every report format.
+var Formats = []string{FormatText, FormatJSON, FormatJUnit}
+
+// Predefined errors
+var (
+	UnknownFormatError = errors.New("unknown report format")
+)
+
+// Write prints the report in format: text for people, JSON for scripts, or
+// JUnit XML for CI systems that annotate failed tests.
+func (r Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatText, "":
+		return r.writeText(w)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	case FormatJUnit:
+		return r.writeJUnit(w)
+	default:
+		return fmt.Errorf("%w %q, expected %s", UnknownFormatError, format, strings.Join(Formats, ", "))
+	}
+}
+
+var statusMarks = map[Status]string{Pass: "✓", Fail: "✗", Skip: "-"}
+
+func (r Report) writeText(w io.Writer) error {
+	target := ""
+	if r.Target != "" {
+		target = fmt.Sprintf(" into '%s'", r.Target)
+	}
+	if _, err := fmt.Fprintf(w, "Changelog entry for '%s'%s:\n", r.Branch, target); err != nil {
+		return err
+	}
+	for _, result := range r.Results {
+		if _, err := fmt.Fprintf(w, "%s %s: %s\n", statusMarks[result.Status], result.Name, result.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *junitMessage `xml:"failure"`
+	Skipped   *junitMessage `xml:"skipped"`
+	Output    string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes one test suite for the branch with a test case per
+// check.
+func (r Report) writeJUnit(w io.Writer) error {
+	suite := junitSuite{Name: "changelog entry for " + r.Branch}
+	for _, result := range r.Results {
+		testCase := junitCase{Name: result.Name, ClassName: "changelog-go.check", File: r.File}
+		switch result.Status {
+		case Fail:
+			testCase.Failure = &junitMessage{Message: result.Message, Text: result.Message}
+			suite.Failures++
+		case Skip:
+			testCase.Skipped = &junitMessage{Message: result.Message}
+			suite.Skipped++
+		default:
+			testCase.Output = result.Message
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	suites := junitSuites{
+		Name:     "changelog-go check",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Suites:   []junitSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
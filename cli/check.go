@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abirhasanmubin/changelog-go/changelog"
+	"github.com/abirhasanmubin/changelog-go/check"
+	"github.com/abirhasanmubin/changelog-go/command"
+)
+
+const checkUsage = "check [--target branch] [--branch name] [--format text|json|junit]"
+
+func checkCommand() *Command {
+	return &Command{
+		Name:    "check",
+		Usage:   checkUsage,
+		Summary: "Validate the entry for the current branch, for CI",
+		Run:     runCheck,
+	}
+}
+
+func runCheck(app *App, args []string) error {
+	flags := app.newFlagSet("check", checkUsage)
+	target := flags.String("target", "", "target `branch`, the entry's target branch by default")
+	branch := flags.String("branch", "", "branch `name` whose entry is checked, the current branch by default")
+	format := flags.String("format", check.FormatText, "report `format`: "+strings.Join(check.Formats, ", "))
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("%w: unexpected argument %q", UsageError, flags.Arg(0))
+	}
+	if !containsString(check.Formats, *format) {
+		return fmt.Errorf("%w: unknown --format %q", UsageError, *format)
+	}
+
+	cfg, err := app.config()
+	if err != nil {
+		return err
+	}
+	cmd := command.New()
+	// HEAD is what CI checked out, which may be a detached head or a merge
+	// commit rather than the branch itself, so it is compared unless
+	// --branch names another branch than the checked out one.
+	head := "HEAD"
+	current, err := cmd.GetCurrentBranch()
+	if *branch == "" {
+		if err != nil || current == "HEAD" {
+			return fmt.Errorf("%w: not on a branch, pass --branch", UsageError)
+		}
+		*branch = current
+	} else if err == nil && current != "HEAD" {
+		head = *branch
+	}
+
+	report, err := check.Run(check.Options{
+		EntryDir:           cfg.EntryDir(),
+		Root:               cfg.Root,
+		Branch:             *branch,
+		Target:             *target,
+		Checklist:          cfg.Checklist,
+		TestingChangeTypes: cfg.TestingChangeTypes(),
+		// Commits adding or updating the entry cannot be listed in it, so
+		// they are skipped.
+		ListCommits: func(target string) ([]changelog.GitCommit, error) {
+			return changelog.BranchCommits(cmd, target, head, cfg.ExcludedPaths()...)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := report.Write(app.Stdout, *format); err != nil {
+		return err
+	}
+	if !report.Passed {
+		return fmt.Errorf("%w: %d of %d checks failed", CheckFailedError, len(report.Failures()), len(report.Results))
+	}
+	return nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,280 @@
+// Package cli implements the changelog-go command tree.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/abirhasanmubin/changelog-go/command"
+	"github.com/abirhasanmubin/changelog-go/config"
+	"github.com/abirhasanmubin/changelog-go/forge"
+)
+
+// Exit codes returned by App.Run.
+const (
+	ExitOK    = 0
+	ExitError = 1
+	ExitUsage = 2
+)
+
+// Predefined errors
+var (
+	UsageError        = errors.New("invalid usage")
+	DoctorFailedError = errors.New("one or more required checks failed")
+	MissingEntryError = errors.New("no changelog entry for branch")
+	CheckFailedError  = errors.New("changelog entry check failed")
+	TagExistsError    = errors.New("tag already exists")
+	StagedFilesError  = errors.New("files are already staged")
+
+	// flagsReportedError marks flag errors the flag package already printed.
+	flagsReportedError = fmt.Errorf("%w: bad flags", UsageError)
+)
+
+// Command is a single subcommand such as "new" or "list".
+type Command struct {
+	Name    string
+	Usage   string
+	Summary string
+	Run     func(app *App, args []string) error
+}
+
+// App holds the registered commands and the streams they write to.
+type App struct {
+	Stdout   io.Writer
+	Stderr   io.Writer
+	Version  string
+	commands []*Command
+	cfg      *config.Config
+	// fetch is the fetch policy given on the command line, overriding the
+	// config.
+	fetch command.FetchPolicy
+	// Context stops the git commands of a run when done. Nil runs them
+	// until they finish or time out.
+	Context context.Context
+}
+
+// New returns an App with every changelog-go command registered.
+func New() *App {
+	app := &App{
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+		Version: "dev",
+	}
+	app.commands = []*Command{
+		newCommand(),
+		listCommand(),
+		showCommand(),
+		editCommand(),
+		checkCommand(),
+		releaseCommand(),
+		versionCommand(),
+		renderCommand(),
+		hooksCommand(),
+		doctorCommand(),
+	}
+	return app
+}
+
+// Run parses the global flags, dispatches to the named command and returns
+// the process exit code.
+func (a *App) Run(args []string) int {
+	ctx := a.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer saveDefaults().restore()
+	command.DefaultContext = ctx
+	// The fetcher remembers its fetches, so every run gets its own.
+	command.DefaultFetcher = &command.Fetcher{Policy: command.DefaultFetcher.Policy}
+
+	flags := flag.NewFlagSet("changelog-go", flag.ContinueOnError)
+	flags.SetOutput(a.Stderr)
+	flags.Usage = func() { a.printUsage(a.Stderr) }
+	dir := flags.String("C", "", "run as if started in `path`")
+	showVersion := flags.Bool("version", false, "print the version and exit")
+	fetch := flags.String("fetch", "", "when to fetch from the remote: always, once or `never`")
+	offline := flags.Bool("offline", false, "never fetch, same as --fetch never")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+
+	if *showVersion {
+		fmt.Fprintf(a.Stdout, "changelog-go %s\n", a.Version)
+		return ExitOK
+	}
+
+	if *offline {
+		*fetch = string(command.FetchNever)
+	}
+	if *fetch != "" {
+		policy, err := command.ParseFetchPolicy(*fetch)
+		if err != nil {
+			fmt.Fprintf(a.Stderr, "changelog-go: %v\n", err)
+			return ExitUsage
+		}
+		a.fetch = policy
+		command.DefaultFetcher.SetPolicy(policy)
+	}
+
+	if *dir != "" {
+		if err := os.Chdir(*dir); err != nil {
+			fmt.Fprintf(a.Stderr, "changelog-go: %v\n", err)
+			return ExitError
+		}
+	}
+
+	if flags.NArg() == 0 {
+		a.printUsage(a.Stderr)
+		return ExitUsage
+	}
+
+	name := flags.Arg(0)
+	if name == "help" {
+		return a.help(flags.Args()[1:])
+	}
+
+	cmd := a.lookup(name)
+	if cmd == nil {
+		fmt.Fprintf(a.Stderr, "changelog-go: unknown command %q\n\n", name)
+		a.printUsage(a.Stderr)
+		return ExitUsage
+	}
+
+	if err := cmd.Run(a, flags.Args()[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		if err == flagsReportedError {
+			return ExitUsage
+		}
+		fmt.Fprintf(a.Stderr, "changelog-go %s: %v\n", cmd.Name, err)
+		if errors.Is(err, UsageError) {
+			fmt.Fprintf(a.Stderr, "usage: changelog-go %s\n", cmd.Usage)
+			return ExitUsage
+		}
+		return ExitError
+	}
+	return ExitOK
+}
+
+func (a *App) help(args []string) int {
+	if len(args) == 0 {
+		a.printUsage(a.Stdout)
+		return ExitOK
+	}
+	cmd := a.lookup(args[0])
+	if cmd == nil {
+		fmt.Fprintf(a.Stderr, "changelog-go: unknown command %q\n", args[0])
+		return ExitUsage
+	}
+	fmt.Fprintf(a.Stdout, "usage: changelog-go %s\n\n%s\n", cmd.Usage, cmd.Summary)
+	return ExitOK
+}
+
+// config loads the repository configuration, once per run.
+func (a *App) config() (config.Config, error) {
+	if a.cfg == nil {
+		cfg, err := config.Load()
+		if err != nil {
+			return cfg, err
+		}
+		a.cfg = &cfg
+		if a.fetch == "" {
+			command.DefaultFetcher.SetPolicy(cfg.Git.Fetch)
+		}
+		if cfg.Git.Remote != "" {
+			command.DefaultRemote = cfg.Git.Remote
+		}
+		command.DefaultBaseBranches = cfg.Git.BaseBranches
+		cfg.RegisterForges()
+	}
+	return *a.cfg, nil
+}
+
+// defaults are the process-wide settings a run changes from its flags and
+// config, put back when it returns so they do not leak into the next one.
+type defaults struct {
+	context      context.Context
+	fetcher      *command.Fetcher
+	remote       string
+	baseBranches []string
+	forges       map[string]forge.Host
+}
+
+func saveDefaults() defaults {
+	return defaults{
+		context:      command.DefaultContext,
+		fetcher:      command.DefaultFetcher,
+		remote:       command.DefaultRemote,
+		baseBranches: command.DefaultBaseBranches,
+		forges:       forge.Hosts(),
+	}
+}
+
+func (d defaults) restore() {
+	command.DefaultContext = d.context
+	command.DefaultFetcher = d.fetcher
+	command.DefaultRemote = d.remote
+	command.DefaultBaseBranches = d.baseBranches
+	forge.SetHosts(d.forges)
+}
+
+func (a *App) lookup(name string) *Command {
+	for _, cmd := range a.commands {
+		if cmd.Name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+func (a *App) printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: changelog-go [-C path] [--fetch policy | --offline] [--version] <command> [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	width := 0
+	for _, cmd := range a.commands {
+		if len(cmd.Name) > width {
+			width = len(cmd.Name)
+		}
+	}
+	for _, cmd := range a.commands {
+		fmt.Fprintf(w, "  %s%s  %s\n", cmd.Name, strings.Repeat(" ", width-len(cmd.Name)), cmd.Summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'changelog-go help <command>' for details on a command.")
+}
+
+// newFlagSet returns a flag set for cmd whose usage output goes to the
+// app's error stream.
+func (a *App) newFlagSet(cmd string, usage string) *flag.FlagSet {
+	flags := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	flags.SetOutput(a.Stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(a.Stderr, "usage: changelog-go %s\n", usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseFlags parses args, leaving error reporting to the flag package.
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return flagsReportedError
+	}
+	return nil
+}
@@ -0,0 +1,693 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abirhasanmubin/changelog-go/changelog"
+	"github.com/abirhasanmubin/changelog-go/command"
+	"github.com/abirhasanmubin/changelog-go/forge"
+)
+
+func newTestApp() (*App, *bytes.Buffer, *bytes.Buffer) {
+	var stdout, stderr bytes.Buffer
+	app := New()
+	app.Stdout = &stdout
+	app.Stderr = &stderr
+	return app, &stdout, &stderr
+}
+
+// chdir switches into dir for the duration of the test.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(cwd) })
+}
+
+func writeEntry(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(changelog.DefaultDir, 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(changelog.DefaultDir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write entry: %v", err)
+	}
+}
+
+func writeConfigFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func TestApp_Run_Usage(t *testing.T) {
+	t.Run("no arguments prints usage", func(t *testing.T) {
+		app, _, stderr := newTestApp()
+		if code := app.Run(nil); code != ExitUsage {
+			t.Errorf("expected exit code %d, got %d", ExitUsage, code)
+		}
+		for _, name := range []string{"new", "list", "show", "edit", "check", "release", "render", "doctor"} {
+			if !strings.Contains(stderr.String(), "  "+name) {
+				t.Errorf("expected usage to list %q", name)
+			}
+		}
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		app, _, stderr := newTestApp()
+		if code := app.Run([]string{"bogus"}); code != ExitUsage {
+			t.Errorf("expected exit code %d, got %d", ExitUsage, code)
+		}
+		if !strings.Contains(stderr.String(), `unknown command "bogus"`) {
+			t.Errorf("expected unknown command message, got %q", stderr.String())
+		}
+	})
+
+	t.Run("help for a command", func(t *testing.T) {
+		app, stdout, _ := newTestApp()
+		if code := app.Run([]string{"help", "show"}); code != ExitOK {
+			t.Errorf("expected exit code %d, got %d", ExitOK, code)
+		}
+		if !strings.Contains(stdout.String(), "usage: changelog-go show [file]") {
+			t.Errorf("expected show usage, got %q", stdout.String())
+		}
+	})
+
+	t.Run("version flag", func(t *testing.T) {
+		app, stdout, _ := newTestApp()
+		app.Version = "1.2.3"
+		if code := app.Run([]string{"--version"}); code != ExitOK {
+			t.Errorf("expected exit code %d, got %d", ExitOK, code)
+		}
+		if strings.TrimSpace(stdout.String()) != "changelog-go 1.2.3" {
+			t.Errorf("unexpected version output %q", stdout.String())
+		}
+	})
+
+	t.Run("unexpected argument", func(t *testing.T) {
+		app, _, stderr := newTestApp()
+		if code := app.Run([]string{"list", "extra"}); code != ExitUsage {
+			t.Errorf("expected exit code %d, got %d", ExitUsage, code)
+		}
+		if !strings.Contains(stderr.String(), "usage: changelog-go list") {
+			t.Errorf("expected list usage, got %q", stderr.String())
+		}
+	})
+
+	t.Run("unknown flag", func(t *testing.T) {
+		app, _, _ := newTestApp()
+		if code := app.Run([]string{"list", "--bogus"}); code != ExitUsage {
+			t.Errorf("expected exit code %d, got %d", ExitUsage, code)
+		}
+	})
+}
+
+func TestApp_Run_List(t *testing.T) {
+	t.Run("no entries", func(t *testing.T) {
+		chdir(t, t.TempDir())
+		app, stdout, stderr := newTestApp()
+		if code := app.Run([]string{"list"}); code != ExitOK {
+			t.Errorf("expected exit code %d, got %d", ExitOK, code)
+		}
+		if stdout.Len() != 0 {
+			t.Errorf("expected no output, got %q", stdout.String())
+		}
+		if !strings.Contains(stderr.String(), "No entries found") {
+			t.Errorf("expected no entries message, got %q", stderr.String())
+		}
+	})
+
+	t.Run("lists entries oldest first", func(t *testing.T) {
+		chdir(t, t.TempDir())
+		writeEntry(t, "1700000100_user_feature-b.md", "## Title\n\nB\n")
+		writeEntry(t, "1700000000_user_feature-a.md", "## Title\n\nA\n")
+		writeEntry(t, "notes.txt", "ignored")
+
+		app, stdout, _ := newTestApp()
+		if code := app.Run([]string{"list"}); code != ExitOK {
+			t.Errorf("expected exit code %d, got %d", ExitOK, code)
+		}
+		lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 entries, got %d: %q", len(lines), stdout.String())
+		}
+		if !strings.HasSuffix(lines[0], "1700000000_user_feature-a.md") {
+			t.Errorf("expected oldest entry first, got %q", lines[0])
+		}
+	})
+}
+
+func TestApp_Run_Show(t *testing.T) {
+	t.Run("shows named entry", func(t *testing.T) {
+		chdir(t, t.TempDir())
+		writeEntry(t, "1700000000_user_a.md", "## Title\n\nFirst\n")
+		writeEntry(t, "1700000100_user_b.md", "## Title\n\nSecond\n")
+
+		app, stdout, _ := newTestApp()
+		if code := app.Run([]string{"show", "1700000000_user_a.md"}); code != ExitOK {
+			t.Errorf("expected exit code %d, got %d", ExitOK, code)
+		}
+		if !strings.Contains(stdout.String(), "First") {
+			t.Errorf("expected first entry, got %q", stdout.String())
+		}
+	})
+
+	t.Run("shows latest entry by default", func(t *testing.T) {
+		chdir(t, t.TempDir())
+		writeEntry(t, "1700000000_user_a.md", "## Title\n\nFirst\n")
+		writeEntry(t, "1700000100_user_b.md", "## Title\n\nSecond\n")
+
+		app, stdout, _ := newTestApp()
+		if code := app.Run([]string{"show"}); code != ExitOK {
+			t.Errorf("expected exit code %d, got %d", ExitOK, code)
+		}
+		if !strings.Contains(stdout.String(), "Second") {
+			t.Errorf("expected latest entry, got %q", stdout.String())
+		}
+	})
+
+	t.Run("missing entry", func(t *testing.T) {
+		chdir(t, t.TempDir())
+		app, _, stderr := newTestApp()
+		if code := app.Run([]string{"show", "missing.md"}); code != ExitError {
+			t.Errorf("expected exit code %d, got %d", ExitError, code)
+		}
+		if !strings.Contains(stderr.String(), `entry "missing.md" not found`) {
+			t.Errorf("expected not found message, got %q", stderr.String())
+		}
+	})
+}
+
+func TestApp_Run_ChangeDirectory(t *testing.T) {
+	cwd, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(cwd) })
+
+	dir := t.TempDir()
+	app, _, _ := newTestApp()
+	if code := app.Run([]string{"-C", dir, "list"}); code != ExitOK {
+		t.Errorf("expected exit code %d, got %d", ExitOK, code)
+	}
+	got, _ := os.Getwd()
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil && got != resolved && got != dir {
+		t.Errorf("expected working directory %q, got %q", dir, got)
+	}
+}
+
+// runProbe runs a command recording the Commands a command gets once the
+// config is loaded, with the global flags in args.
+func runProbe(t *testing.T, args ...string) (command.Commands, int) {
+	t.Helper()
+	app, _, _ := newTestApp()
+	var commands command.Commands
+	app.commands = append(app.commands, &Command{Name: "probe", Run: func(app *App, _ []string) error {
+		if _, err := app.config(); err != nil {
+			return err
+		}
+		commands = command.New()
+		return nil
+	}})
+	code := app.Run(append(args, "probe"))
+	return commands, code
+}
+
+func TestApp_Run_FetchPolicy(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	if commands, code := runProbe(t, "--offline"); code != ExitOK || commands.Fetch.Policy != command.FetchNever {
+		t.Errorf("expected --offline to disable fetching, got %q (exit %d)", commands.Fetch.Policy, code)
+	}
+
+	writeConfigFile(t, ".changelog.yaml", "git:\n  fetch: always\n")
+	if commands, code := runProbe(t, "--fetch", "never"); code != ExitOK || commands.Fetch.Policy != command.FetchNever {
+		t.Errorf("expected the flag to override the config, got %q (exit %d)", commands.Fetch.Policy, code)
+	}
+	if commands, code := runProbe(t); code != ExitOK || commands.Fetch.Policy != command.FetchAlways {
+		t.Errorf("expected the config policy, got %q (exit %d)", commands.Fetch.Policy, code)
+	}
+	if command.DefaultFetcher.Policy != command.FetchOnce {
+		t.Errorf("expected the default policy back after the runs, got %q", command.DefaultFetcher.Policy)
+	}
+
+	app, _, stderr := newTestApp()
+	if code := app.Run([]string{"--fetch", "sometimes", "list"}); code != ExitUsage {
+		t.Errorf("expected exit code %d, got %d", ExitUsage, code)
+	}
+	if !strings.Contains(stderr.String(), "unknown fetch policy") {
+		t.Errorf("unexpected stderr %q", stderr.String())
+	}
+}
+
+func TestApp_Run_RestoresDefaults(t *testing.T) {
+	chdir(t, t.TempDir())
+	writeConfigFile(t, ".changelog.yaml", "git:\n  remote: upstream\n  base_branches: [trunk]\nforges:\n  - host: git.example.com\n    type: gitlab\n")
+	baseBranches := command.DefaultBaseBranches
+
+	commands, code := runProbe(t)
+	if code != ExitOK {
+		t.Fatalf("expected exit code %d, got %d", ExitOK, code)
+	}
+	if commands.Remote != "upstream" || strings.Join(commands.BaseBranches, ",") != "trunk" {
+		t.Errorf("expected the configured remote and base branches, got %q and %q", commands.Remote, commands.BaseBranches)
+	}
+	if command.DefaultRemote != "" || strings.Join(command.DefaultBaseBranches, ",") != strings.Join(baseBranches, ",") {
+		t.Errorf("expected the defaults back, got %q and %q", command.DefaultRemote, command.DefaultBaseBranches)
+	}
+	if _, ok := forge.Hosts()["git.example.com"]; ok {
+		t.Error("expected the configured forge to be unregistered after the run")
+	}
+}
+
+func TestApp_Run_Context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	app, _, _ := newTestApp()
+	app.Context = ctx
+	var runContext context.Context
+	var stopped bool
+	app.commands = append(app.commands, &Command{Name: "probe", Run: func(*App, []string) error {
+		runContext = command.New().Context
+		stopped = runContext != nil && runContext.Err() != nil
+		return nil
+	}})
+
+	if code := app.Run([]string{"probe"}); code != ExitOK {
+		t.Fatalf("expected exit code %d, got %d", ExitOK, code)
+	}
+	if runContext == nil || stopped {
+		t.Fatal("expected the commands to get the run's context")
+	}
+	if runContext.Err() == nil || command.DefaultContext != nil {
+		t.Error("expected the run's context to end with the run")
+	}
+
+	cancel()
+	app.Run([]string{"probe"})
+	if !stopped {
+		t.Error("expected cancelling the app's context to stop the commands")
+	}
+}
+
+func TestApp_Run_Release(t *testing.T) {
+	chdir(t, t.TempDir())
+	writeEntry(t, "1700000000_user_fix.md", "## Title\n\nFix login\n\n## Type of change\n\n- [x] Bug fix\n- [ ] New feature\n")
+
+	app, stdout, _ := newTestApp()
+	if code := app.Run([]string{"release", "--dry-run", "--date", "2024-03-01", "v1.4.0"}); code != ExitOK {
+		t.Fatalf("expected exit code %d, got %d", ExitOK, code)
+	}
+	if stdout.String() != "## [1.4.0] - 2024-03-01\n\n### Bug fix\n\n- Fix login\n" {
+		t.Errorf("unexpected dry run output %q", stdout.String())
+	}
+
+	app, stdout, _ = newTestApp()
+	if code := app.Run([]string{"release", "v1.4.0"}); code != ExitOK {
+		t.Fatalf("expected exit code %d, got %d", ExitOK, code)
+	}
+	if !strings.Contains(stdout.String(), "Released 1.4.0 with 1 entries to CHANGELOG.md") {
+		t.Errorf("unexpected output %q", stdout.String())
+	}
+	if _, err := os.Stat("CHANGELOG.md"); err != nil {
+		t.Errorf("expected CHANGELOG.md to be written: %v", err)
+	}
+
+	app, _, _ = newTestApp()
+	if code := app.Run([]string{"release"}); code != ExitUsage {
+		t.Errorf("expected exit code %d without a version, got %d", ExitUsage, code)
+	}
+}
+
+func TestApp_Run_ReleaseSplitComponents(t *testing.T) {
+	chdir(t, t.TempDir())
+	writeConfigFile(t, ".changelog.yaml", "release:\n  components: split\ncomponents:\n  - name: api\n    paths: [services/api/**]\n")
+	os.MkdirAll(filepath.Join("services", "api"), 0755)
+	writeEntry(t, "1700000000_user_fix.md", "## Title\n\nFix login\n\n## Type of change\n\n- [x] Bug fix\n")
+	writeEntry(t, "1700000100_user_limit.md", "## Title\n\nRate limit\n\n## Components\n\n- api\n\n## Type of change\n\n- [x] New feature\n")
+
+	app, stdout, _ := newTestApp()
+	if code := app.Run([]string{"release", "--dry-run", "--date", "2024-03-01", "v1.4.0"}); code != ExitOK {
+		t.Fatalf("expected exit code %d, got %d", ExitOK, code)
+	}
+	want := "==> CHANGELOG.md <==\n## [1.4.0] - 2024-03-01\n\n### Bug fix\n\n- Fix login\n\n" +
+		"==> services/api/CHANGELOG.md <==\n## [1.4.0] - 2024-03-01\n\n### New feature\n\n- Rate limit\n"
+	if stdout.String() != want {
+		t.Errorf("unexpected dry run output\n%s\nwant\n%s", stdout.String(), want)
+	}
+
+	app, stdout, _ = newTestApp()
+	if code := app.Run([]string{"release", "v1.4.0"}); code != ExitOK {
+		t.Fatalf("expected exit code %d, got %d", ExitOK, code)
+	}
+	if !strings.Contains(stdout.String(), "Released 1.4.0 with 2 entries to CHANGELOG.md, services/api/CHANGELOG.md") {
+		t.Errorf("unexpected output %q", stdout.String())
+	}
+	if content, err := os.ReadFile(filepath.Join("services", "api", "CHANGELOG.md")); err != nil || !strings.Contains(string(content), "- Rate limit\n") {
+		t.Errorf("expected the component changelog to be written, got %q, %v", content, err)
+	}
+}
+
+// gitInit turns the working directory into a repository with one commit.
+func gitInit(t *testing.T) {
+	t.Helper()
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"config", "user.email", "tester@example.com"},
+		{"config", "user.name", "tester"},
+		{"commit", "--quiet", "--allow-empty", "-m", "init"},
+	} {
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Skipf("git %s failed: %v: %s", args[0], err, output)
+		}
+	}
+}
+
+func TestApp_Run_VersionAndBump(t *testing.T) {
+	chdir(t, t.TempDir())
+	gitInit(t)
+	if err := exec.Command("git", "tag", "v1.3.2").Run(); err != nil {
+		t.Fatalf("failed to tag: %v", err)
+	}
+	writeEntry(t, "1700000000_user_export.md", "## Title\n\nCSV export\n\n## Type of change\n\n- [ ] Bug fix\n- [x] New feature\n")
+
+	app, stdout, _ := newTestApp()
+	if code := app.Run([]string{"version"}); code != ExitOK {
+		t.Fatalf("expected exit code %d, got %d", ExitOK, code)
+	}
+	if stdout.String() != "v1.4.0\n" {
+		t.Errorf("unexpected next version %q", stdout.String())
+	}
+
+	app, stdout, _ = newTestApp()
+	if code := app.Run([]string{"version", "--pre", "rc"}); code != ExitOK || stdout.String() != "v1.4.0-rc.1\n" {
+		t.Errorf("unexpected pre-release %q (exit %d)", stdout.String(), code)
+	}
+
+	app, stdout, _ = newTestApp()
+	if code := app.Run([]string{"release", "--bump", "--tag", "--date", "2024-03-01"}); code != ExitOK {
+		t.Fatalf("expected exit code %d, got %d", ExitOK, code)
+	}
+	if !strings.Contains(stdout.String(), "Released 1.4.0") || !strings.Contains(stdout.String(), "Tagged v1.4.0") {
+		t.Errorf("unexpected output %q", stdout.String())
+	}
+	message, err := exec.Command("git", "tag", "--list", "--format=%(contents)", "v1.4.0").Output()
+	if err != nil {
+		t.Fatalf("failed to read tag: %v", err)
+	}
+	if !strings.HasPrefix(string(message), "## [1.4.0] - 2024-03-01\n\n### New feature\n\n- CSV export") {
+		t.Errorf("expected the release notes as tag message, got %q", message)
+	}
+	if content, err := exec.Command("git", "show", "v1.4.0:CHANGELOG.md").Output(); err != nil || !strings.Contains(string(content), "## [1.4.0]") {
+		t.Errorf("expected the tag to hold the released changelog (%v):\n%s", err, content)
+	}
+	if status, _ := exec.Command("git", "status", "--porcelain").Output(); len(status) != 0 {
+		t.Errorf("expected the release to be committed, got\n%s", status)
+	}
+
+	writeEntry(t, "1700000100_user_import.md", "## Title\n\nCSV import\n\n## Type of change\n\n- [x] New feature\n")
+	if err := exec.Command("git", "tag", "v1.5.0").Run(); err != nil {
+		t.Fatalf("failed to tag: %v", err)
+	}
+	app, _, stderr := newTestApp()
+	if code := app.Run([]string{"release", "--tag", "v1.5.0"}); code != ExitError || !strings.Contains(stderr.String(), "tag already exists") {
+		t.Errorf("expected an existing tag to stop the release (exit %d): %q", code, stderr.String())
+	}
+	gitCommit(t, "Add import entry", "staged.txt", "staged\n")
+	os.WriteFile("staged.txt", []byte("changed\n"), 0644)
+	exec.Command("git", "add", "staged.txt").Run()
+	app, _, stderr = newTestApp()
+	if code := app.Run([]string{"release", "--tag", "v1.6.0"}); code != ExitError || !strings.Contains(stderr.String(), "files are already staged") {
+		t.Errorf("expected staged files to stop the release (exit %d): %q", code, stderr.String())
+	}
+	if entries, _ := changelog.ListEntryFiles(changelog.DefaultDir); len(entries) != 1 {
+		t.Errorf("expected the entry to stay unreleased, got %v", entries)
+	}
+
+	for _, args := range [][]string{
+		{"release", "--bump", "v2.0.0"},
+		{"release", "--pre", "rc", "v2.0.0"},
+		{"version", "extra"},
+	} {
+		app, _, _ = newTestApp()
+		if code := app.Run(args); code != ExitUsage {
+			t.Errorf("%v: expected exit code %d, got %d", args, ExitUsage, code)
+		}
+	}
+}
+
+func TestApp_Run_Hooks(t *testing.T) {
+	chdir(t, t.TempDir())
+	gitInit(t)
+	if err := exec.Command("git", "checkout", "--quiet", "-b", "feature/login").Run(); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+	hook := filepath.Join(".git", "hooks", "pre-push")
+	writeConfigFile(t, hook, "#!/bin/sh\necho existing\n")
+
+	app, stdout, _ := newTestApp()
+	if code := app.Run([]string{"hooks", "install"}); code != ExitOK || !strings.Contains(stdout.String(), "Installed the pre-push hook") {
+		t.Fatalf("unexpected install (exit %d): %q", code, stdout.String())
+	}
+	content, _ := os.ReadFile(hook)
+	if !strings.Contains(string(content), "hooks run pre-push") || !strings.HasSuffix(string(content), "echo existing\n") {
+		t.Errorf("unexpected hook:\n%s", content)
+	}
+	app, stdout, _ = newTestApp()
+	if code := app.Run([]string{"hooks", "install"}); code != ExitOK || !strings.Contains(stdout.String(), "already installed") {
+		t.Errorf("expected a second install to change nothing (exit %d): %q", code, stdout.String())
+	}
+
+	app, _, stderr := newTestApp()
+	if code := app.Run([]string{"hooks", "run", "pre-push"}); code != ExitError || !strings.Contains(stderr.String(), "no changelog entry for branch 'feature/login'") {
+		t.Errorf("expected the push to be blocked (exit %d): %q", code, stderr.String())
+	}
+	writeEntry(t, "1700000000_tester_feature-login.md", "## Title\n\nLogin\n\n## Commit List\n\nCommits from branch 'feature/login':\n")
+	app, _, stderr = newTestApp()
+	if code := app.Run([]string{"hooks", "run", "pre-push"}); code != ExitOK {
+		t.Errorf("expected the push to go ahead (exit %d): %q", code, stderr.String())
+	}
+	archived := filepath.Join(changelog.DefaultDir, changelog.ArchiveDir, "1.0.0")
+	if err := os.MkdirAll(archived, 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.Rename(filepath.Join(changelog.DefaultDir, "1700000000_tester_feature-login.md"), filepath.Join(archived, "1700000000_tester_feature-login.md")); err != nil {
+		t.Fatalf("failed to archive entry: %v", err)
+	}
+	app, _, stderr = newTestApp()
+	if code := app.Run([]string{"hooks", "run", "pre-push"}); code != ExitOK {
+		t.Errorf("expected a released entry to let the push go ahead (exit %d): %q", code, stderr.String())
+	}
+
+	app, stdout, _ = newTestApp()
+	if code := app.Run([]string{"hooks", "uninstall"}); code != ExitOK || !strings.Contains(stdout.String(), "Removed the pre-push hook") {
+		t.Errorf("unexpected uninstall (exit %d): %q", code, stdout.String())
+	}
+	if content, _ := os.ReadFile(hook); string(content) != "#!/bin/sh\necho existing\n" {
+		t.Errorf("expected the existing hook back, got\n%s", content)
+	}
+
+	app, _, _ = newTestApp()
+	if code := app.Run([]string{"hooks", "remove"}); code != ExitUsage {
+		t.Errorf("expected exit code %d, got %d", ExitUsage, code)
+	}
+}
+
+// gitCommit commits everything in the working directory, after writing
+// content to file when it is set.
+func gitCommit(t *testing.T, message, file, content string) {
+	t.Helper()
+	if file != "" {
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", file, err)
+		}
+	}
+	for _, args := range [][]string{{"add", "-A"}, {"commit", "--quiet", "-m", message}} {
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v: %s", args[0], err, output)
+		}
+	}
+}
+
+func TestApp_Run_Check(t *testing.T) {
+	chdir(t, t.TempDir())
+	gitInit(t)
+	for _, args := range [][]string{
+		{"branch", "-M", "main"},
+		{"checkout", "--quiet", "-b", "feature/login"},
+	} {
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v: %s", args[0], err, output)
+		}
+	}
+	gitCommit(t, "fix: login redirect", "login.go", "package login\n")
+
+	app, stdout, _ := newTestApp()
+	if code := app.Run([]string{"check", "--target", "main"}); code != ExitError || !strings.Contains(stdout.String(), "✗ entry: no entry for branch 'feature/login'") {
+		t.Errorf("expected a missing entry to fail (exit %d):\n%s", code, stdout.String())
+	}
+
+	app, _, stderr := newTestApp()
+	if code := app.Run([]string{"--offline", "new", "--non-interactive", "--title", "Fix login", "--type", "Bug fix", "--target", "main"}); code != ExitOK {
+		t.Fatalf("failed to write an entry (exit %d): %s", code, stderr.String())
+	}
+	app, stdout, stderr = newTestApp()
+	if code := app.Run([]string{"--offline", "check"}); code != ExitOK {
+		t.Errorf("expected the entry to pass (exit %d):\n%s%s", code, stdout.String(), stderr.String())
+	}
+
+	// The commit adding the entry is never listed in it.
+	gitCommit(t, "Add changelog entry", "", "")
+	app, stdout, stderr = newTestApp()
+	if code := app.Run([]string{"--offline", "check", "--target", "main"}); code != ExitOK {
+		t.Errorf("expected the committed entry to pass (exit %d):\n%s%s", code, stdout.String(), stderr.String())
+	}
+
+	// An explicit --branch is compared rather than the checked out branch.
+	if output, err := exec.Command("git", "checkout", "--quiet", "-b", "other").CombinedOutput(); err != nil {
+		t.Fatalf("git checkout failed: %v: %s", err, output)
+	}
+	gitCommit(t, "chore: unrelated", "other.go", "package other\n")
+	app, stdout, stderr = newTestApp()
+	if code := app.Run([]string{"--offline", "check", "--target", "main", "--branch", "feature/login"}); code != ExitOK {
+		t.Errorf("expected the named branch's commits to be compared (exit %d):\n%s%s", code, stdout.String(), stderr.String())
+	}
+	if output, err := exec.Command("git", "checkout", "--quiet", "feature/login").CombinedOutput(); err != nil {
+		t.Fatalf("git checkout failed: %v: %s", err, output)
+	}
+
+	gitCommit(t, "fix: typo", "login.go", "package login // fixed\n")
+	app, stdout, stderr = newTestApp()
+	if code := app.Run([]string{"--offline", "check", "--target", "main", "--format", "json"}); code != ExitError {
+		t.Errorf("expected a stale commit list to fail (exit %d)", code)
+	}
+	if !strings.Contains(stdout.String(), `"passed": false`) || !strings.Contains(stdout.String(), "1 commit missing from the entry") {
+		t.Errorf("unexpected JSON report:\n%s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "1 of 4 checks failed") {
+		t.Errorf("unexpected error output %q", stderr.String())
+	}
+
+	app, stdout, _ = newTestApp()
+	app.Run([]string{"--offline", "check", "--target", "main", "--format", "junit"})
+	if !strings.HasPrefix(stdout.String(), "<?xml") || !strings.Contains(stdout.String(), `<testcase name="commits"`) {
+		t.Errorf("unexpected JUnit report:\n%s", stdout.String())
+	}
+
+	app, _, _ = newTestApp()
+	if code := app.Run([]string{"check", "--format", "sarif"}); code != ExitUsage {
+		t.Errorf("expected exit code %d, got %d", ExitUsage, code)
+	}
+}
+
+func TestResolveEntryOrBranch(t *testing.T) {
+	chdir(t, t.TempDir())
+	writeEntry(t, "1700000000_user_feature-login.md", "## Title\n\nOld\n\n## Commit List\n\nCommits from branch 'feature/login':\n")
+	writeEntry(t, "1700000100_user_feature-login.md", "## Title\n\nNew\n\n## Commit List\n\nCommits from branch 'feature/login':\n")
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"1700000000_user_feature-login.md", "1700000000_user_feature-login.md", false},
+		{"feature/login", "1700000100_user_feature-login.md", false},
+		{"feature/other", "", true},
+	}
+	for _, tt := range tests {
+		path, err := resolveEntryOrBranch(changelog.DefaultDir, tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("resolveEntryOrBranch(%q) error = %v", tt.name, err)
+		}
+		if tt.want != "" && filepath.Base(path) != tt.want {
+			t.Errorf("resolveEntryOrBranch(%q) = %q, want %q", tt.name, path, tt.want)
+		}
+	}
+}
+
+func TestApp_Run_EditUsage(t *testing.T) {
+	chdir(t, t.TempDir())
+	app, _, stderr := newTestApp()
+	if code := app.Run([]string{"edit"}); code != ExitUsage {
+		t.Errorf("expected exit code %d, got %d", ExitUsage, code)
+	}
+
+	app, _, stderr = newTestApp()
+	if code := app.Run([]string{"edit", "feature/none"}); code != ExitError {
+		t.Errorf("expected exit code %d, got %d", ExitError, code)
+	}
+	if !strings.Contains(stderr.String(), `no entry found for "feature/none"`) {
+		t.Errorf("unexpected error output %q", stderr.String())
+	}
+}
+
+func TestApp_Run_Render(t *testing.T) {
+	chdir(t, t.TempDir())
+	writeEntry(t, "1700000000_user_feature-login.md", "## Title\n\nFix login\n\n## Type of change\n\n- [x] Bug fix\n- [ ] New feature\n\n## Commit List\n\nCommits from branch 'feature/login':\n")
+
+	app, stdout, stderr := newTestApp()
+	if code := app.Run([]string{"render", "feature/login"}); code != ExitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", ExitOK, code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "### Fix login\n") || !strings.Contains(stdout.String(), "- ✅ Bug fix\n") {
+		t.Errorf("expected PR text by default, got\n%s", stdout.String())
+	}
+
+	app, stdout, _ = newTestApp()
+	if code := app.Run([]string{"render", "1700000000_user_feature-login.md", "--format", "markdown"}); code != ExitOK {
+		t.Fatalf("expected exit code %d, got %d", ExitOK, code)
+	}
+	if !strings.HasPrefix(stdout.String(), "## Title\n\nFix login\n") {
+		t.Errorf("expected markdown, got\n%s", stdout.String())
+	}
+
+	for _, args := range [][]string{{"render"}, {"render", "feature/login", "--format", "html"}, {"render", "a", "b"}} {
+		app, _, _ = newTestApp()
+		if code := app.Run(args); code != ExitUsage {
+			t.Errorf("expected exit code %d for %q, got %d", ExitUsage, args, code)
+		}
+	}
+	app, _, _ = newTestApp()
+	if code := app.Run([]string{"render", "feature/other"}); code != ExitError {
+		t.Errorf("expected exit code %d for a missing entry, got %d", ExitError, code)
+	}
+}
+
+func TestApp_Run_NewValidation(t *testing.T) {
+	app, _, stderr := newTestApp()
+	code := app.Run([]string{"new", "--type", "Hotfix", "--checklist", "self-review,deploy"})
+	if code != ExitError {
+		t.Errorf("expected exit code %d, got %d", ExitError, code)
+	}
+	for _, want := range []string{"title is required", `unknown change type "Hotfix"`, `unknown checklist item "deploy"`} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("expected %q in %q", want, stderr.String())
+		}
+	}
+}
+
+func TestApp_Run_NewMissingAnswersFile(t *testing.T) {
+	app, _, _ := newTestApp()
+	if code := app.Run([]string{"new", "--answers", filepath.Join(t.TempDir(), "missing.yaml")}); code != ExitError {
+		t.Errorf("expected exit code %d, got %d", ExitError, code)
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	got := splitList([]string{"self-review, tests", "readme", ""})
+	want := []string{"self-review", "tests", "readme"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := splitList(nil); got == nil || len(got) != 0 {
+		t.Errorf("expected empty non-nil list, got %#v", got)
+	}
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/abirhasanmubin/changelog-go/command"
+	"github.com/abirhasanmubin/changelog-go/hooks"
+	"github.com/abirhasanmubin/changelog-go/utils"
+)
+
+type doctorCheck struct {
+	name     string
+	required bool
+	run      func(cmd command.Commands) (string, error)
+}
+
+func doctorCommand() *Command {
+	return &Command{
+		Name:    "doctor",
+		Usage:   "doctor",
+		Summary: "Check that git, the repository and the clipboard are usable",
+		Run:     runDoctor,
+	}
+}
+
+var doctorChecks = []doctorCheck{
+	{"git installed", true, func(cmd command.Commands) (string, error) {
+		return cmd.Cmd.Run(command.GIT, "--version")
+	}},
+	{"inside a git repository", true, func(cmd command.Commands) (string, error) {
+		return cmd.Cmd.Run(command.GIT, "rev-parse", "--show-toplevel")
+	}},
+	{"current branch", true, func(cmd command.Commands) (string, error) {
+		return cmd.GetCurrentBranch()
+	}},
+	{"username", false, func(cmd command.Commands) (string, error) {
+		return cmd.GetUsername()
+	}},
+	{"remote", false, func(cmd command.Commands) (string, error) {
+		if cmd.Remote != "" {
+			return cmd.Remote + " (configured)", nil
+		}
+		remote, candidates := cmd.DetectRemote()
+		if len(candidates) > 1 {
+			return fmt.Sprintf("%s, guessed among %s; set git.remote to choose", remote, strings.Join(candidates, ", ")), nil
+		}
+		return remote, nil
+	}},
+	{"target branch", false, func(cmd command.Commands) (string, error) {
+		branch, reason := cmd.DetectTargetBranch()
+		if branch == "" {
+			return "", errors.New("not detected; set git.base_branches or pass --target")
+		}
+		return fmt.Sprintf("%s (%s)", branch, reason), nil
+	}},
+	{"commit links", false, func(cmd command.Commands) (string, error) {
+		return cmd.GetCommitHttpUrlPrefixFromRemoteUrl()
+	}},
+	{"pre-push hook", false, func(cmd command.Commands) (string, error) {
+		path, err := prePushHookPath()
+		if err != nil {
+			return "", err
+		}
+		if installed, err := hooks.IsInstalled(path); err != nil || installed {
+			return path, err
+		}
+		return "not installed (changelog-go hooks install)", nil
+	}},
+	{"clipboard", false, func(cmd command.Commands) (string, error) {
+		program, err := utils.ClipboardProgram()
+		if err != nil {
+			return "", err
+		}
+		return exec.LookPath(program)
+	}},
+}
+
+func runDoctor(app *App, args []string) error {
+	flags := app.newFlagSet("doctor", "doctor")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("%w: unexpected argument %q", UsageError, flags.Arg(0))
+	}
+
+	cmd := command.New()
+	failed := false
+
+	if cfg, err := app.config(); err != nil {
+		failed = true
+		fmt.Fprintf(app.Stdout, "✗ config: %v\n", err)
+	} else if cfg.Path != "" {
+		fmt.Fprintf(app.Stdout, "✓ config: %s\n", cfg.Path)
+	} else {
+		fmt.Fprintf(app.Stdout, "✓ config: built-in defaults\n")
+	}
+
+	for _, check := range doctorChecks {
+		detail, err := check.run(cmd)
+		switch {
+		case err == nil:
+			fmt.Fprintf(app.Stdout, "✓ %s: %s\n", check.name, detail)
+		case check.required:
+			failed = true
+			fmt.Fprintf(app.Stdout, "✗ %s: %s\n", check.name, command.Reason(err))
+		default:
+			fmt.Fprintf(app.Stdout, "! %s: %s\n", check.name, command.Reason(err))
+		}
+	}
+	if failed {
+		return DoctorFailedError
+	}
+	return nil
+}
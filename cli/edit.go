@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/abirhasanmubin/changelog-go/changelog"
+	"github.com/abirhasanmubin/changelog-go/prompt"
+)
+
+const editUsage = "edit [--section name] <file|branch>"
+
+func editCommand() *Command {
+	return &Command{
+		Name:    "edit",
+		Usage:   editUsage,
+		Summary: "Re-open an existing entry with its answers as defaults",
+		Run:     runEdit,
+	}
+}
+
+func runEdit(app *App, args []string) error {
+	flags := app.newFlagSet("edit", editUsage)
+	section := flags.String("section", "", "only ask for `name`: "+strings.Join(prompt.EditSections(), ", "))
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("%w: expected an entry file or branch", UsageError)
+	}
+
+	cfg, err := app.config()
+	if err != nil {
+		return err
+	}
+	path, err := resolveEntryOrBranch(cfg.EntryDir(), flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	return prompt.Edit(cfg, path, *section)
+}
+
+// resolveEntryOrBranch finds an entry by file name or path, then by the
+// branch it was written on.
+func resolveEntryOrBranch(dir, name string) (string, error) {
+	if path, err := resolveEntryPath(dir, name); err == nil {
+		return path, nil
+	}
+	file, err := changelog.LatestEntryForBranch(dir, name)
+	if err != nil {
+		return "", err
+	}
+	if file == "" {
+		return "", fmt.Errorf("no entry found for %q in %s", name, dir)
+	}
+	return filepath.Join(dir, file), nil
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/abirhasanmubin/changelog-go/changelog"
+	"github.com/abirhasanmubin/changelog-go/command"
+	"github.com/abirhasanmubin/changelog-go/hooks"
+	"github.com/abirhasanmubin/changelog-go/prompt"
+)
+
+const hooksUsage = "hooks install [--wizard] | hooks uninstall"
+
+func hooksCommand() *Command {
+	return &Command{
+		Name:    "hooks",
+		Usage:   hooksUsage,
+		Summary: "Install or remove the pre-push hook requiring an entry",
+		Run:     runHooks,
+	}
+}
+
+func runHooks(app *App, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: expected install or uninstall", UsageError)
+	}
+	switch args[0] {
+	case "install":
+		return runHooksInstall(app, args[1:])
+	case "uninstall":
+		return runHooksUninstall(app, args[1:])
+	case "run":
+		return runHooksRun(app, args[1:])
+	default:
+		return fmt.Errorf("%w: unknown hooks command %q", UsageError, args[0])
+	}
+}
+
+func runHooksInstall(app *App, args []string) error {
+	flags := app.newFlagSet("hooks install", hooksUsage)
+	wizard := flags.Bool("wizard", false, "start the wizard instead of only blocking the push")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("%w: unexpected argument %q", UsageError, flags.Arg(0))
+	}
+
+	path, err := prePushHookPath()
+	if err != nil {
+		return err
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the changelog-go executable: %w", err)
+	}
+	status, err := hooks.Install(path, hooks.Script(executable, *wizard))
+	if err != nil {
+		return err
+	}
+	switch status {
+	case hooks.Unchanged:
+		fmt.Fprintf(app.Stdout, "The %s hook in %s is already installed\n", hooks.PrePush, path)
+	case hooks.Updated:
+		fmt.Fprintf(app.Stdout, "Updated the %s hook in %s\n", hooks.PrePush, path)
+	default:
+		fmt.Fprintf(app.Stdout, "Installed the %s hook in %s\n", hooks.PrePush, path)
+	}
+	return nil
+}
+
+func runHooksUninstall(app *App, args []string) error {
+	flags := app.newFlagSet("hooks uninstall", hooksUsage)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("%w: unexpected argument %q", UsageError, flags.Arg(0))
+	}
+
+	path, err := prePushHookPath()
+	if err != nil {
+		return err
+	}
+	status, err := hooks.Uninstall(path)
+	if err != nil {
+		return err
+	}
+	if status == hooks.NotInstalled {
+		fmt.Fprintf(app.Stdout, "No changelog-go %s hook in %s\n", hooks.PrePush, path)
+		return nil
+	}
+	fmt.Fprintf(app.Stdout, "Removed the %s hook from %s\n", hooks.PrePush, path)
+	return nil
+}
+
+// runHooksRun is what the installed hook calls: "hooks run pre-push".
+func runHooksRun(app *App, args []string) error {
+	flags := app.newFlagSet("hooks run", "hooks run pre-push [--wizard]")
+	wizard := flags.Bool("wizard", false, "start the wizard when the entry is missing")
+	if len(args) == 0 || args[0] != hooks.PrePush {
+		return fmt.Errorf("%w: expected the %s hook", UsageError, hooks.PrePush)
+	}
+	if err := parseFlags(flags, args[1:]); err != nil {
+		return err
+	}
+
+	cfg, err := app.config()
+	if err != nil {
+		return err
+	}
+	cmd := command.New()
+	branch, err := cmd.GetCurrentBranch()
+	if err != nil || branch == "HEAD" || cmd.IsBaseBranch(branch) {
+		return nil
+	}
+	if file, err := changelog.LatestEntryForBranch(cfg.EntryDir(), branch); err != nil || file != "" {
+		return err
+	}
+
+	if *wizard {
+		fmt.Fprintf(app.Stderr, "No changelog entry for branch '%s' yet, starting the wizard; the push continues once it is saved.\n", branch)
+		if err := prompt.GenerateWithConfig(cfg); err != nil {
+			return err
+		}
+		if file, err := changelog.LatestEntryForBranch(cfg.EntryDir(), branch); err != nil || file != "" {
+			return err
+		}
+	}
+	return fmt.Errorf("%w '%s' in %s: run 'changelog-go new' to write one, or push with --no-verify to skip this check", MissingEntryError, branch, cfg.Output.Dir)
+}
+
+// prePushHookPath is where git looks for the pre-push hook of the
+// repository in the working directory.
+func prePushHookPath() (string, error) {
+	dir, err := command.New().GetHooksDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the hooks directory: %w", err)
+	}
+	if !filepath.IsAbs(dir) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(cwd, dir)
+	}
+	return filepath.Join(dir, hooks.PrePush), nil
+}
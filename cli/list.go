@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/abirhasanmubin/changelog-go/changelog"
+)
+
+func listCommand() *Command {
+	return &Command{
+		Name:    "list",
+		Usage:   "list",
+		Summary: "List saved changelog entries",
+		Run:     runList,
+	}
+}
+
+func showCommand() *Command {
+	return &Command{
+		Name:    "show",
+		Usage:   "show [file]",
+		Summary: "Print a saved entry, the most recent one by default",
+		Run:     runShow,
+	}
+}
+
+func runList(app *App, args []string) error {
+	flags := app.newFlagSet("list", "list")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("%w: unexpected argument %q", UsageError, flags.Arg(0))
+	}
+
+	cfg, err := app.config()
+	if err != nil {
+		return err
+	}
+	files, err := changelog.ListEntryFiles(cfg.EntryDir())
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Fprintf(app.Stderr, "No entries found in %s\n", cfg.Output.Dir)
+		return nil
+	}
+	for _, file := range files {
+		created := "unknown"
+		if createdAt, ok := changelog.CreatedAt(file); ok {
+			created = createdAt.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(app.Stdout, "%s  %s\n", created, file)
+	}
+	return nil
+}
+
+func runShow(app *App, args []string) error {
+	flags := app.newFlagSet("show", "show [file]")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		return fmt.Errorf("%w: unexpected argument %q", UsageError, flags.Arg(1))
+	}
+
+	cfg, err := app.config()
+	if err != nil {
+		return err
+	}
+	path, err := resolveEntryPath(cfg.EntryDir(), flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(app.Stdout, string(content))
+	return nil
+}
+
+// resolveEntryPath finds an entry by name inside dir, falling back to
+// treating name as a path. An empty name selects the most recent entry.
+func resolveEntryPath(dir, name string) (string, error) {
+	if name == "" {
+		files, err := changelog.ListEntryFiles(dir)
+		if err != nil {
+			return "", err
+		}
+		if len(files) == 0 {
+			return "", fmt.Errorf("no entries found in %s", dir)
+		}
+		return filepath.Join(dir, files[len(files)-1]), nil
+	}
+
+	inDir := filepath.Join(dir, name)
+	if _, err := os.Stat(inDir); err == nil {
+		return inDir, nil
+	}
+	if _, err := os.Stat(name); err != nil {
+		return "", fmt.Errorf("entry %q not found", name)
+	}
+	return name, nil
+}
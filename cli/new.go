@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/abirhasanmubin/changelog-go/prompt"
+)
+
+const newUsage = "new [--answers file] [--title text] [--type name]... [--checklist id]... [flags]"
+
+func newCommand() *Command {
+	return &Command{
+		Name:    "new",
+		Usage:   newUsage,
+		Summary: "Create a changelog entry with the wizard, or from flags and an answers file",
+		Run:     runNew,
+	}
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+func runNew(app *App, args []string) error {
+	flags := app.newFlagSet("new", newUsage)
+	answersFile := flags.String("answers", "", "read answers from a JSON or YAML `file`")
+	nonInteractive := flags.Bool("non-interactive", false, "never prompt, fail on missing answers")
+	title := flags.String("title", "", "entry title")
+	motivation := flags.String("motivation", "", "why the change is made")
+	description := flags.String("description", "", "description of the change")
+	target := flags.String("target", "", "target `branch` to collect commits against")
+	output := flags.String("output", "", "output format: file, copy or show (default file)")
+	var types, todos, modelChanges, testing, checklist, components stringList
+	flags.Var(&types, "type", "change `type`, repeatable; use \"Other: <text>\" for a custom type")
+	flags.Var(&todos, "todo", "instruction before merge, repeatable")
+	flags.Var(&modelChanges, "model-change", "change to an existing model, repeatable")
+	flags.Var(&testing, "testing", "testing step, repeatable")
+	flags.Var(&checklist, "checklist", "checked checklist item `id`, repeatable")
+	flags.Var(&components, "component", "`name` of a component the change belongs to, repeatable; detected from the changed files by default")
+
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("%w: unexpected argument %q", UsageError, flags.Arg(0))
+	}
+
+	cfg, err := app.config()
+	if err != nil {
+		return err
+	}
+
+	set := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	delete(set, "non-interactive")
+	if len(set) == 0 && !*nonInteractive {
+		return prompt.GenerateWithConfig(cfg)
+	}
+
+	var answers prompt.Answers
+	if *answersFile != "" {
+		loaded, err := prompt.LoadAnswers(*answersFile)
+		if err != nil {
+			return err
+		}
+		answers = loaded
+	}
+
+	// Flags override the answers file.
+	if set["title"] {
+		answers.Title = *title
+	}
+	if set["motivation"] {
+		answers.Motivation = *motivation
+	}
+	if set["description"] {
+		answers.Description = *description
+	}
+	if set["target"] {
+		answers.TargetBranch = *target
+	}
+	if set["output"] {
+		answers.Output = *output
+	}
+	if set["type"] {
+		answers.Types = types
+	}
+	if set["todo"] {
+		answers.Todos = todos
+	}
+	if set["model-change"] {
+		answers.ModelChanges = modelChanges
+	}
+	if set["testing"] {
+		answers.Testing = testing
+	}
+	if set["checklist"] {
+		answers.Checklist = splitList(checklist)
+	}
+	if set["component"] {
+		answers.Components = splitList(components)
+	}
+
+	return prompt.GenerateFromAnswers(cfg, answers)
+}
+
+// splitList flattens comma-separated values so "--checklist a,b" and
+// "--checklist a --checklist b" are equivalent.
+func splitList(values []string) []string {
+	result := []string{}
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
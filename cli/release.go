@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/abirhasanmubin/changelog-go/command"
+	"github.com/abirhasanmubin/changelog-go/release"
+)
+
+const releaseUsage = "release [--date YYYY-MM-DD] [--dry-run] [--tag] [--ticket pattern]... (--bump [--pre id] | <version>)"
+
+func releaseCommand() *Command {
+	return &Command{
+		Name:    "release",
+		Usage:   releaseUsage,
+		Summary: "Compile unreleased entries into CHANGELOG.md",
+		Run:     runRelease,
+	}
+}
+
+func runRelease(app *App, args []string) error {
+	flags := app.newFlagSet("release", releaseUsage)
+	date := flags.String("date", "", "release `date`, today by default")
+	dryRun := flags.Bool("dry-run", false, "print the release section without writing or archiving")
+	bump := flags.Bool("bump", false, "compute the version from the latest tag and the entries' change types")
+	pre := flags.String("pre", "", "with --bump, release a pre-release with this `identifier`, such as rc")
+	tag := flags.Bool("tag", false, "commit the release and tag it with the release notes as the tag's message")
+	var tickets stringList
+	flags.Var(&tickets, "ticket", "release only entries with a ticket matching this `pattern`, such as PAY-*; repeatable")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	switch {
+	case *bump && flags.NArg() != 0:
+		return fmt.Errorf("%w: --bump does not take a version", UsageError)
+	case !*bump && *pre != "":
+		return fmt.Errorf("%w: --pre needs --bump", UsageError)
+	case !*bump && flags.NArg() != 1:
+		return fmt.Errorf("%w: expected exactly one version", UsageError)
+	}
+
+	releaseDate := time.Now()
+	if *date != "" {
+		parsed, err := time.Parse("2006-01-02", *date)
+		if err != nil {
+			return fmt.Errorf("%w: invalid --date %q, expected YYYY-MM-DD", UsageError, *date)
+		}
+		releaseDate = parsed
+	}
+
+	cfg, err := app.config()
+	if err != nil {
+		return err
+	}
+	version, tagName := flags.Arg(0), strings.TrimSpace(flags.Arg(0))
+	if *bump {
+		plan, err := planRelease(cfg, *pre, tickets)
+		if err != nil {
+			return err
+		}
+		version, tagName = plan.Next.String(), plan.NextTag()
+		fmt.Fprintf(app.Stderr, "Bumping %s to %s (%s)\n", plan.Current, plan.Next, plan.Bump)
+	}
+
+	cmd := command.New()
+	if *tag {
+		if err := checkTaggable(cmd, tagName, *dryRun); err != nil {
+			return err
+		}
+	}
+
+	result, err := release.Run(release.Options{
+		Version:     version,
+		Date:        releaseDate,
+		EntryDir:    cfg.EntryDir(),
+		File:        cfg.ReleaseFile(),
+		ChangeTypes: cfg.ChangeTypes,
+		DryRun:      *dryRun,
+		Tickets:     tickets,
+		Components:  cfg.ReleaseComponents(),
+		Split:       cfg.Release.Components == release.ComponentsSplit,
+	})
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		if len(result.Changelogs) == 1 && result.Changelogs[0].File == cfg.ReleaseFile() {
+			fmt.Fprint(app.Stdout, result.Section)
+			return nil
+		}
+		for i, target := range result.Changelogs {
+			if i > 0 {
+				fmt.Fprintln(app.Stdout)
+			}
+			fmt.Fprintf(app.Stdout, "==> %s <==\n%s", relativePath(cfg.Root, target.File), target.Section)
+		}
+		return nil
+	}
+	var files []string
+	for _, target := range result.Changelogs {
+		files = append(files, relativePath(cfg.Root, target.File))
+	}
+	fmt.Fprintf(app.Stdout, "Released %s with %d entries to %s\n", result.Version, len(result.Entries), strings.Join(files, ", "))
+	fmt.Fprintf(app.Stdout, "Archived entries to %s\n", result.ArchivedTo)
+
+	if *tag {
+		// The tag goes on a commit holding the release, not on the HEAD
+		// the changelogs were rewritten on.
+		changed := []string{result.ArchivedTo}
+		var removed []string
+		for _, target := range result.Changelogs {
+			changed = append(changed, target.File)
+		}
+		for _, entry := range result.Entries {
+			removed = append(removed, filepath.Join(cfg.EntryDir(), entry))
+		}
+		if err := cmd.CommitFiles("Release "+tagName, changed, removed); err != nil {
+			return fmt.Errorf("failed to commit the release, commit and tag %s yourself: %w", tagName, err)
+		}
+		if err := cmd.CreateAnnotatedTag(tagName, result.Section); err != nil {
+			return fmt.Errorf("failed to create tag %s on the release commit: %w", tagName, err)
+		}
+		fmt.Fprintf(app.Stdout, "Committed the release\nTagged %s\n", tagName)
+	}
+	return nil
+}
+
+// checkTaggable makes sure --tag can commit and tag the release before
+// anything is written: the tag is new, and nothing else is staged that
+// would end up in the release commit.
+func checkTaggable(cmd command.Commands, tagName string, dryRun bool) error {
+	tags, err := cmd.GetTags()
+	if err != nil {
+		return fmt.Errorf("failed to list tags: %w", err)
+	}
+	if containsString(tags, tagName) {
+		return fmt.Errorf("%w: %s", TagExistsError, tagName)
+	}
+	if dryRun {
+		return nil
+	}
+	staged, err := cmd.HasStagedChanges()
+	if err != nil {
+		return fmt.Errorf("failed to read the index: %w", err)
+	}
+	if staged {
+		return fmt.Errorf("%w: commit or unstage them first, --tag commits the release", StagedFilesError)
+	}
+	return nil
+}
+
+// relativePath shows path relative to the repository root when it is
+// inside it.
+func relativePath(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
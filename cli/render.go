@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abirhasanmubin/changelog-go/changelog"
+)
+
+const renderUsage = "render <file|branch> [--format pr|markdown]"
+
+func renderCommand() *Command {
+	return &Command{
+		Name:    "render",
+		Usage:   renderUsage,
+		Summary: "Render an entry in another output format",
+		Run:     runRender,
+	}
+}
+
+func runRender(app *App, args []string) error {
+	flags := app.newFlagSet("render", renderUsage)
+	format := flags.String("format", changelog.TemplatePR, "output `format`: "+strings.Join(changelog.TemplateNames, ", "))
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	// Flags may also follow the entry.
+	name := flags.Arg(0)
+	if flags.NArg() > 1 {
+		if err := parseFlags(flags, flags.Args()[1:]); err != nil {
+			return err
+		}
+		if flags.NArg() != 0 {
+			return fmt.Errorf("%w: unexpected argument %q", UsageError, flags.Arg(0))
+		}
+	}
+	if name == "" {
+		return fmt.Errorf("%w: expected an entry file or branch", UsageError)
+	}
+	if !containsString(changelog.TemplateNames, *format) {
+		return fmt.Errorf("%w: unknown --format %q", UsageError, *format)
+	}
+
+	cfg, err := app.config()
+	if err != nil {
+		return err
+	}
+	path, err := resolveEntryOrBranch(cfg.EntryDir(), name)
+	if err != nil {
+		return err
+	}
+	entry, selectedTypes, err := changelog.ParseFile(path)
+	if err != nil {
+		return err
+	}
+	if entry.Templates, err = cfg.LoadTemplates(); err != nil {
+		return err
+	}
+	entry.FileList = cfg.FileList()
+
+	content, err := entry.Render(*format, selectedTypes)
+	if err != nil {
+		return fmt.Errorf("failed to render %s: %w", *format, err)
+	}
+	fmt.Fprint(app.Stdout, content)
+	return nil
+}
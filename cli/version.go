@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/abirhasanmubin/changelog-go/command"
+	"github.com/abirhasanmubin/changelog-go/config"
+	"github.com/abirhasanmubin/changelog-go/release"
+)
+
+const versionUsage = "version [--pre id]"
+
+func versionCommand() *Command {
+	return &Command{
+		Name:    "version",
+		Usage:   versionUsage,
+		Summary: "Print the next version the unreleased entries call for",
+		Run:     runVersion,
+	}
+}
+
+func runVersion(app *App, args []string) error {
+	flags := app.newFlagSet("version", versionUsage)
+	pre := flags.String("pre", "", "pre-release `identifier`, such as rc")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("%w: unexpected arguments", UsageError)
+	}
+
+	cfg, err := app.config()
+	if err != nil {
+		return err
+	}
+	plan, err := planRelease(cfg, *pre, nil)
+	if err != nil {
+		return err
+	}
+	current := plan.CurrentTag
+	if current == "" {
+		current = "no version tag"
+	}
+	fmt.Fprintf(app.Stderr, "%s -> %s (%s)\n", current, plan.NextTag(), plan.Bump)
+	fmt.Fprintln(app.Stdout, plan.NextTag())
+	return nil
+}
+
+// planRelease works out the next version from the repository's tags and
+// the unreleased entries, only those with matching tickets when tickets is
+// set.
+func planRelease(cfg config.Config, pre string, tickets []string) (release.Plan, error) {
+	tags, err := command.New().GetTags()
+	if err != nil {
+		return release.Plan{}, fmt.Errorf("failed to read tags: %w", err)
+	}
+	entries, err := release.Unreleased(cfg.EntryDir())
+	if err != nil {
+		return release.Plan{}, err
+	}
+	if len(tickets) > 0 {
+		entries = release.FilterTickets(entries, tickets)
+	}
+	return release.PlanNext(tags, entries, pre)
+}
@@ -2,12 +2,16 @@ package command
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
-	"regexp"
+	"os/signal"
 	"strings"
+	"time"
+
+	"github.com/abirhasanmubin/changelog-go/forge"
 )
 
 // Predefined errors
@@ -17,6 +21,11 @@ var (
 	NoUsernameFoundError       = errors.New("no username found")
 	NoGitBranchFoundError      = errors.New("no git branch found, please checkout to a branch")
 	NoCommitHttpUrlPrefixError = errors.New("no http url prefix found for current repo")
+	TimeoutError               = errors.New("command timed out")
+	InterruptedError           = errors.New("command interrupted")
+	GitNotFoundError           = errors.New("git is not installed or not in PATH")
+	NotARepositoryError        = errors.New("not a git repository")
+	AuthError                  = errors.New("authentication failed")
 )
 
 type CommandType int
@@ -40,9 +49,42 @@ const (
 type Commander interface {
 	Run(ct CommandType, args ...string) (string, error)
 }
-type CommandRunner struct{}
 
-func (CommandRunner) Run(ct CommandType, args ...string) (string, error) {
+// ContextCommander is a Commander whose commands stop when ctx is done.
+type ContextCommander interface {
+	Commander
+	RunContext(ctx context.Context, ct CommandType, args ...string) (string, error)
+}
+
+// Default timeouts of CommandRunner.
+const (
+	DefaultTimeout        = 30 * time.Second
+	DefaultNetworkTimeout = time.Minute
+)
+
+// networkCommands are the git subcommands that talk to a remote.
+var networkCommands = map[string]bool{"fetch": true, "ls-remote": true, "pull": true, "push": true}
+
+// CommandRunner runs commands in the working directory. Git never prompts
+// for credentials, and Ctrl+C stops the running command instead of the
+// whole program.
+type CommandRunner struct {
+	// Timeout bounds each local command, DefaultTimeout when zero.
+	Timeout time.Duration
+	// NetworkTimeout bounds git commands that talk to a remote, such as
+	// fetch, DefaultNetworkTimeout when zero.
+	NetworkTimeout time.Duration
+}
+
+func (r CommandRunner) Run(ct CommandType, args ...string) (string, error) {
+	return r.RunContext(context.Background(), ct, args...)
+}
+
+// RunContext runs the command until it finishes, its timeout passes, ctx is
+// done or the user interrupts it. Failures wrap RunningCommandError and,
+// when recognised, TimeoutError, InterruptedError, GitNotFoundError,
+// NotARepositoryError or AuthError.
+func (r CommandRunner) RunContext(ctx context.Context, ct CommandType, args ...string) (string, error) {
 	if len(args) == 0 {
 		return "", NoArgumentError
 	}
@@ -58,35 +100,160 @@ func (CommandRunner) Run(ct CommandType, args ...string) (string, error) {
 		return "", fmt.Errorf("unknown command type: %v", ct)
 	}
 
-	cmd := exec.Command(program, args...)
+	timeout := r.timeoutFor(ct, args)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	interrupted, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	cmd := exec.CommandContext(interrupted, program, args...)
 	// Set working directory to current directory
 	if cwd, err := os.Getwd(); err == nil {
 		cmd.Dir = cwd
 	}
+	if ct == GIT {
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GCM_INTERACTIVE=never")
+	}
+	// Helpers such as ssh may keep the output open after git is killed.
+	cmd.WaitDelay = time.Second
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("%w: %s", RunningCommandError, stderr.String())
+		return "", commandError(ct, program, args, err, stderr.String(), ctx, interrupted, timeout)
 	}
 	return strings.TrimSpace(stdout.String()), nil
 }
 
+func (r CommandRunner) timeoutFor(ct CommandType, args []string) time.Duration {
+	if ct == GIT && networkCommands[args[0]] {
+		if r.NetworkTimeout > 0 {
+			return r.NetworkTimeout
+		}
+		return DefaultNetworkTimeout
+	}
+	if r.Timeout > 0 {
+		return r.Timeout
+	}
+	return DefaultTimeout
+}
+
+// Patterns in git's error output that mean the remote refused the
+// credentials or there were none to give.
+var authFailurePatterns = []string{
+	"authentication failed",
+	"permission denied (publickey",
+	"could not read username",
+	"could not read password",
+	"terminal prompts disabled",
+	"host key verification failed",
+	"invalid username or password",
+	"the requested url returned error: 403",
+}
+
+// commandError describes why a command failed, wrapping the typed error
+// that matches.
+func commandError(ct CommandType, program string, args []string, err error, stderr string, timed, interrupted context.Context, timeout time.Duration) error {
+	name := strings.TrimSpace(program + " " + strings.Join(args, " "))
+	lower := strings.ToLower(stderr)
+	switch {
+	case errors.Is(timed.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w after %s: %s", RunningCommandError, TimeoutError, timeout, name)
+	case interrupted.Err() != nil && timed.Err() == nil:
+		return fmt.Errorf("%w: %w: %s", RunningCommandError, InterruptedError, name)
+	case errors.Is(timed.Err(), context.Canceled):
+		return fmt.Errorf("%w: %w: %s", RunningCommandError, timed.Err(), name)
+	case errors.Is(err, exec.ErrNotFound) && ct == GIT:
+		return fmt.Errorf("%w: %w", RunningCommandError, GitNotFoundError)
+	case errors.Is(err, exec.ErrNotFound):
+		return fmt.Errorf("%w: %v", RunningCommandError, err)
+	case strings.Contains(lower, "not a git repository"):
+		return fmt.Errorf("%w: %w: %s", RunningCommandError, NotARepositoryError, stderr)
+	}
+	for _, pattern := range authFailurePatterns {
+		if strings.Contains(lower, pattern) {
+			return fmt.Errorf("%w: %w: %s", RunningCommandError, AuthError, stderr)
+		}
+	}
+	return fmt.Errorf("%w: %s", RunningCommandError, stderr)
+}
+
+// Reason explains err in one line for warnings: the typed error when there
+// is one, otherwise the first line of the command's output.
+func Reason(err error) string {
+	for _, typed := range []error{TimeoutError, InterruptedError, GitNotFoundError, NotARepositoryError, AuthError} {
+		if errors.Is(err, typed) {
+			return typed.Error()
+		}
+	}
+	reason := strings.TrimPrefix(err.Error(), RunningCommandError.Error()+": ")
+	reason, _, _ = strings.Cut(strings.TrimSpace(reason), "\n")
+	if reason == "" {
+		return strings.TrimSuffix(strings.TrimSpace(err.Error()), ":")
+	}
+	return reason
+}
+
 type CommandLists interface {
 	GetUsername() (string, error)
 	GetCurrentBranch() (string, error)
 	GetCommitHttpUrlPrefixFromRemoteUrl() (string, error)
+	GetRepository() (forge.Repository, error)
 	GetBranches() ([]string, error)
-	GetCommitsBetweenBranches(targetBranch, currentBranch string) (string, error)
+	GetCommitLogBetweenBranches(targetBranch, currentBranch string, excludedPaths ...string) (string, error)
+	GetDiffNumstat(targetBranch, currentBranch string) (string, error)
+	CheckMailmap(contacts []string) ([]string, error)
+	GetRepositoryRoot() (string, error)
+	GetHooksDir() (string, error)
+	GetTags() ([]string, error)
+	CreateAnnotatedTag(name, message string) error
+	HasStagedChanges() (bool, error)
+	CommitFiles(message string, changed, removed []string) error
 }
 
 type Commands struct {
 	Cmd Commander
+	// Fetch decides when the remote is fetched. Nil fetches every time.
+	Fetch *Fetcher
+	// Context stops running commands when done. Nil never does.
+	Context context.Context
+	// Remote is where target branches and commit links come from. Empty
+	// picks one with DetectRemote.
+	Remote string
+	// BaseBranches are the branches, or path.Match patterns such as
+	// release/*, DetectTargetBranch considers. Nil uses DefaultBaseBranches.
+	BaseBranches []string
 }
 
-func getGitUsername(c Commander) (string, error) {
-	email, err := c.Run(GIT, "config", "--local", "user.email")
+// DefaultRemote is the Remote of the Commands returned from New, set once
+// the remote for a run is known.
+var DefaultRemote string
+
+// DefaultContext is the Context of the Commands returned from New, set to
+// the context of a run. Nil never stops commands.
+var DefaultContext context.Context
+
+// run runs a command with c.Context when Cmd accepts one.
+func (c Commands) run(ct CommandType, args ...string) (string, error) {
+	return runContext(c.Context, c.Cmd, ct, args...)
+}
+
+func runContext(ctx context.Context, c Commander, ct CommandType, args ...string) (string, error) {
+	if runner, ok := c.(ContextCommander); ok && ctx != nil {
+		return runner.RunContext(ctx, ct, args...)
+	}
+	return c.Run(ct, args...)
+}
+
+// New returns Commands running real git and sharing DefaultFetcher, with
+// DefaultContext, DefaultRemote and DefaultBaseBranches.
+func New() Commands {
+	return Commands{Cmd: CommandRunner{}, Fetch: DefaultFetcher, Context: DefaultContext, Remote: DefaultRemote, BaseBranches: DefaultBaseBranches}
+}
+
+func (c Commands) getGitUsername() (string, error) {
+	email, err := c.run(GIT, "config", "--local", "user.email")
 	if err != nil {
 		return "", err
 	}
@@ -94,8 +261,8 @@ func getGitUsername(c Commander) (string, error) {
 	return username, nil
 }
 
-func getLocalUsername(c Commander) (string, error) {
-	username, err := c.Run(OS, "whoami")
+func (c Commands) getLocalUsername() (string, error) {
+	username, err := c.run(OS, "whoami")
 	if err != nil {
 		return "", err
 	}
@@ -103,11 +270,11 @@ func getLocalUsername(c Commander) (string, error) {
 }
 
 func (c Commands) GetUsername() (string, error) {
-	username, err := getGitUsername(c.Cmd)
+	username, err := c.getGitUsername()
 	if err == nil && len(username) != 0 {
 		return username, nil
 	}
-	username, err = getLocalUsername(c.Cmd)
+	username, err = c.getLocalUsername()
 	if err == nil && len(username) != 0 {
 		return username, nil
 	}
@@ -115,11 +282,11 @@ func (c Commands) GetUsername() (string, error) {
 }
 
 func (c Commands) GetCurrentBranch() (string, error) {
-	branch, _ := c.Cmd.Run(GIT, "rev-parse", "--abbrev-ref", "HEAD")
+	branch, _ := c.run(GIT, "rev-parse", "--abbrev-ref", "HEAD")
 	if len(branch) != 0 {
 		return branch, nil
 	}
-	branch, err := c.Cmd.Run(GIT, "branch", "--show-current")
+	branch, err := c.run(GIT, "branch", "--show-current")
 	if err != nil {
 		return "", err
 	}
@@ -130,61 +297,260 @@ func (c Commands) GetCurrentBranch() (string, error) {
 }
 
 func (c Commands) GetCommitHttpUrlPrefixFromRemoteUrl() (string, error) {
-	url, err := c.Cmd.Run(GIT, "config", "--get", "remote.origin.url")
+	repo, err := c.GetRepository()
 	if err != nil {
 		return "", err
 	}
+	return repo.CommitURL(""), nil
+}
 
-	if strings.HasPrefix(url, "git@") {
-		// SSH format: git@github.com:user/repo.git
-		re := regexp.MustCompile(`^git@([^:]+):([^/]+)\/(.+)$`)
-		matches := re.FindStringSubmatch(url)
-		if len(matches) == 4 {
-			host, user, repo := matches[1], matches[2], matches[3]
-			repo = strings.TrimSuffix(repo, ".git")
-			return fmt.Sprintf("https://%s/%s/%s/commit/", host, user, repo), nil
+// GetRepository reads where the remote is hosted, for building links to
+// commits, comparisons and pull requests.
+func (c Commands) GetRepository() (forge.Repository, error) {
+	url, err := c.run(GIT, "config", "--get", "remote."+c.remote()+".url")
+	if err != nil {
+		return forge.Repository{}, err
+	}
+	repo, err := forge.Parse(url)
+	if err != nil {
+		return forge.Repository{}, fmt.Errorf("%w: %v", NoCommitHttpUrlPrefixError, err)
+	}
+	return repo, nil
+}
+
+// GetRemotes lists the configured remotes.
+func (c Commands) GetRemotes() ([]string, error) {
+	output, err := c.run(GIT, "remote")
+	if err != nil {
+		return nil, err
+	}
+	var remotes []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			remotes = append(remotes, line)
 		}
-	} else if strings.HasPrefix(url, "http") {
-		// HTTP/HTTPS format: https://github.com/user/repo.git
-		url = strings.TrimSuffix(url, ".git")
-		return url + "/commit/", nil
 	}
+	return remotes, nil
+}
 
-	return "", NoCommitHttpUrlPrefixError
+// GetTrackingRemote returns the remote branch's upstream is on, empty when
+// it has none or tracks a local branch.
+func (c Commands) GetTrackingRemote(branch string) (string, error) {
+	remote, err := c.run(GIT, "config", "--get", "branch."+branch+".remote")
+	if err != nil || remote == "." {
+		return "", err
+	}
+	return remote, nil
 }
 
+// DetectRemote picks a remote without asking: the current branch's
+// tracking remote, the only remote, or origin. When several remotes could
+// be meant, candidates lists them and remote is only a guess.
+func (c Commands) DetectRemote() (remote string, candidates []string) {
+	if branch, err := c.GetCurrentBranch(); err == nil {
+		if remote, _ := c.GetTrackingRemote(branch); remote != "" {
+			return remote, nil
+		}
+	}
+	remotes, _ := c.GetRemotes()
+	switch len(remotes) {
+	case 0:
+		return "origin", nil
+	case 1:
+		return remotes[0], nil
+	}
+	for _, remote := range remotes {
+		if remote == "origin" {
+			return remote, remotes
+		}
+	}
+	return remotes[0], remotes
+}
 
+func (c Commands) remote() string {
+	if c.Remote != "" {
+		return c.Remote
+	}
+	remote, _ := c.DetectRemote()
+	return remote
+}
 
+// GetBranches lists the branches of the remote without its prefix,
+// followed by other remotes' branches with theirs. When there are no
+// remote-tracking branches, for example before the first fetch of an
+// offline clone, the local branches are listed instead.
 func (c Commands) GetBranches() ([]string, error) {
-	// Fetch latest changes from remote
-	_, _ = c.Cmd.Run(GIT, "fetch", "origin")
+	remote := c.remote()
+	c.Fetch.Fetch(c.Context, c.Cmd, remote)
 
-	output, err := c.Cmd.Run(GIT, "branch", "-r", "--format=%(refname:short)")
+	output, err := c.run(GIT, "branch", "-r", "--format=%(refname:short)")
 	if err != nil {
 		return nil, err
 	}
 
-	var branches []string
+	var branches, others []string
 	for _, line := range strings.Split(output, "\n") {
 		line = strings.TrimSpace(line)
-		if line != "" && !strings.Contains(line, "HEAD") {
-			// Remove origin/ prefix
-			if strings.HasPrefix(line, "origin/") {
-				line = strings.TrimPrefix(line, "origin/")
-			}
+		if line == "" || !strings.Contains(line, "/") || strings.HasSuffix(line, "/HEAD") {
+			continue
+		}
+		if branch, ok := strings.CutPrefix(line, remote+"/"); ok {
+			branches = append(branches, branch)
+		} else {
+			others = append(others, line)
+		}
+	}
+	if branches = append(branches, others...); len(branches) > 0 {
+		return branches, nil
+	}
+	return c.getLocalBranches()
+}
+
+func (c Commands) getLocalBranches() ([]string, error) {
+	output, err := c.run(GIT, "branch", "--format=%(refname:short)")
+	if err != nil {
+		return nil, err
+	}
+	var branches []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
 			branches = append(branches, line)
 		}
 	}
 	return branches, nil
 }
 
-func (c Commands) GetCommitsBetweenBranches(targetBranch, currentBranch string) (string, error) {
-	// Fetch latest changes from remote
-	_, _ = c.Cmd.Run(GIT, "fetch", "origin")
+// targetRef returns the ref commits are compared against: the branch of
+// the remote, a remote branch given with its remote such as
+// "upstream/main", or the local branch when the remote one is not known.
+func (c Commands) targetRef(targetBranch string) string {
+	remoteRef := c.remote() + "/" + targetBranch
+	for _, ref := range []string{remoteRef, targetBranch} {
+		if _, err := c.run(GIT, "rev-parse", "--verify", "--quiet", "refs/remotes/"+ref); err == nil {
+			return ref
+		}
+	}
+	if _, err := c.run(GIT, "rev-parse", "--verify", "--quiet", "refs/heads/"+targetBranch); err == nil {
+		return targetBranch
+	}
+	return remoteRef
+}
+
+// Separators used by GetCommitLogBetweenBranches.
+const (
+	CommitFieldSeparator  = "\x1f"
+	CommitRecordSeparator = "\x1e"
+)
+
+// commitLogFormat is the --format of GetCommitLogBetweenBranches.
+var commitLogFormat = strings.Join([]string{
+	"%H", "%an", "%ae", "%aI", "%cI", "%P", "%G?", "%s", "%(trailers:only,unfold)", "%b",
+}, "%x1f") + "%x1e"
+
+// GetCommitLogBetweenBranches lists the commits on currentBranch missing from
+// targetBranch with their full messages, compared against targetRef.
+// Records end with CommitRecordSeparator and hold, separated by
+// CommitFieldSeparator: the full hash, author name, author email, author
+// date, commit date (both strict ISO 8601), parent hashes separated by
+// spaces, signature status (git's %G? letter), subject, trailers one per
+// line, and body. Commits that only change files under
+// excludedPaths, relative to the repository root, are left out.
+func (c Commands) GetCommitLogBetweenBranches(targetBranch, currentBranch string, excludedPaths ...string) (string, error) {
+	c.Fetch.Fetch(c.Context, c.Cmd, c.remote())
+
+	args := []string{"log", fmt.Sprintf("%s..%s", c.targetRef(targetBranch), currentBranch), "--no-merges", "--format=" + commitLogFormat}
+	if len(excludedPaths) > 0 {
+		args = append(args, "--full-history", "--", ":/")
+		for _, excluded := range excludedPaths {
+			args = append(args, ":(top,exclude)"+excluded)
+		}
+	}
+	return c.run(GIT, args...)
+}
+
+// GetDiffNumstat lists the files changed on currentBranch since it forked
+// from targetBranch, as git diff --numstat -z with renames detected: NUL
+// terminated "added\tdeleted\tpath" records, where renames leave path empty
+// and follow it with the old and new paths, and binary files count "-".
+func (c Commands) GetDiffNumstat(targetBranch, currentBranch string) (string, error) {
+	c.Fetch.Fetch(c.Context, c.Cmd, c.remote())
+
+	return c.run(GIT, "diff", "--numstat", "-z", "--find-renames", fmt.Sprintf("%s...%s", c.targetRef(targetBranch), currentBranch))
+}
+
+// CheckMailmap maps "Name <email>" contacts through the repository's
+// mailmap, in order. Contacts the mailmap does not know come back as given.
+func (c Commands) CheckMailmap(contacts []string) ([]string, error) {
+	if len(contacts) == 0 {
+		return nil, nil
+	}
+	output, err := c.run(GIT, append([]string{"check-mailmap"}, contacts...)...)
+	if err != nil {
+		return nil, err
+	}
+	mapped := strings.Split(output, "\n")
+	if len(mapped) != len(contacts) {
+		return nil, fmt.Errorf("%w: check-mailmap returned %d contacts for %d", RunningCommandError, len(mapped), len(contacts))
+	}
+	return mapped, nil
+}
 
-	commits, err := c.Cmd.Run(GIT, "log", fmt.Sprintf("origin/%s..%s", targetBranch, currentBranch), "--oneline", "--no-merges")
+// GetRepositoryRoot returns the top-level directory of the working tree.
+func (c Commands) GetRepositoryRoot() (string, error) {
+	return c.run(GIT, "rev-parse", "--show-toplevel")
+}
+
+// GetHooksDir returns the directory git runs hooks from, honouring
+// core.hooksPath, relative to the working directory unless absolute.
+func (c Commands) GetHooksDir() (string, error) {
+	return c.run(GIT, "rev-parse", "--git-path", "hooks")
+}
+
+// GetTags lists every tag in the repository.
+func (c Commands) GetTags() ([]string, error) {
+	output, err := c.run(GIT, "tag", "--list")
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	var tags []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			tags = append(tags, line)
+		}
+	}
+	return tags, nil
+}
+
+// CreateAnnotatedTag tags HEAD as name with message kept verbatim, so
+// markdown headings are not taken for comments.
+func (c Commands) CreateAnnotatedTag(name, message string) error {
+	_, err := c.run(GIT, "tag", "--annotate", "--cleanup=verbatim", "--message", message, name)
+	return err
+}
+
+// HasStagedChanges reports whether anything is staged for the next commit.
+func (c Commands) HasStagedChanges() (bool, error) {
+	output, err := c.run(GIT, "diff", "--cached", "--name-only")
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(output) != "", nil
+}
+
+// CommitFiles commits changed as they are on disk, new files included, and
+// the removal of removed, which may never have been tracked. Anything else
+// already staged goes into the commit as well.
+func (c Commands) CommitFiles(message string, changed, removed []string) error {
+	if len(changed) > 0 {
+		if _, err := c.run(GIT, append([]string{"add", "--all", "--"}, changed...)...); err != nil {
+			return err
+		}
+	}
+	if len(removed) > 0 {
+		if _, err := c.run(GIT, append([]string{"rm", "--cached", "--quiet", "--ignore-unmatch", "--"}, removed...)...); err != nil {
+			return err
+		}
 	}
-	return commits, nil
+	_, err := c.run(GIT, "commit", "--quiet", "--message", message)
+	return err
 }
@@ -1,8 +1,13 @@
 package command
 
 import (
+	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 type MockRunner struct {
@@ -309,3 +314,193 @@ func TestCommands_GetUsername_FallbackToLocal(t *testing.T) {
 		}
 	})
 }
+
+func TestCommands_GetCommitLogBetweenBranches(t *testing.T) {
+	runner := &scriptedRunner{responses: map[string]scriptedResponse{
+		"log": {output: "abc1234\x1fJane\x1fjane@example.com\x1f2024-03-01T10:00:00+01:00\x1f2024-03-01T10:00:00+01:00\x1fdef5678\x1fN\x1ffeat: add login\x1f\x1f\x1e"},
+	}}
+	cmd := Commands{Cmd: runner}
+
+	log, err := cmd.GetCommitLogBetweenBranches("main", "feature")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fields := strings.Split(strings.TrimSuffix(log, CommitRecordSeparator), CommitFieldSeparator)
+	if len(fields) != 10 || fields[0] != "abc1234" || fields[7] != "feat: add login" {
+		t.Errorf("unexpected log %q", log)
+	}
+	if !runner.ran("log origin/main..feature") {
+		t.Errorf("expected the remote-tracking branch to be compared, got %q", runner.calls)
+	}
+	if !runner.ran("log origin/main..feature --no-merges --format=%H%x1f%an%x1f") {
+		t.Errorf("expected structured log format, got %q", runner.calls)
+	}
+	if _, err := cmd.GetCommitLogBetweenBranches("main", "feature", ".logs/.changelog"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !runner.ran("log origin/main..feature --no-merges --format=" + commitLogFormat + " --full-history -- :/ :(top,exclude).logs/.changelog") {
+		t.Errorf("expected the excluded paths as pathspecs, got %q", runner.calls)
+	}
+}
+
+func TestCommands_GetDiffNumstat(t *testing.T) {
+	runner := &scriptedRunner{responses: map[string]scriptedResponse{
+		"diff": {output: "1\t2\tREADME.md\x00"},
+	}}
+	cmd := Commands{Cmd: runner}
+
+	numstat, err := cmd.GetDiffNumstat("main", "feature")
+	if err != nil || numstat != "1\t2\tREADME.md\x00" {
+		t.Errorf("unexpected numstat %q, %v", numstat, err)
+	}
+	if !runner.ran("diff --numstat -z --find-renames origin/main...feature") {
+		t.Errorf("expected a diff from the merge base, got %q", runner.calls)
+	}
+}
+
+func TestCommands_CheckMailmap(t *testing.T) {
+	runner := &scriptedRunner{responses: map[string]scriptedResponse{
+		"check-mailmap": {output: "Jane Doe <jane@example.com>\nBob <bob@example.com>"},
+	}}
+	cmd := Commands{Cmd: runner}
+
+	mapped, err := cmd.CheckMailmap([]string{"jdoe <jdoe@laptop>", "Bob <bob@example.com>"})
+	if err != nil || strings.Join(mapped, ",") != "Jane Doe <jane@example.com>,Bob <bob@example.com>" {
+		t.Errorf("unexpected contacts %q, %v", mapped, err)
+	}
+	if !runner.ran("check-mailmap jdoe <jdoe@laptop> Bob <bob@example.com>") {
+		t.Errorf("expected one check-mailmap for every contact, got %q", runner.calls)
+	}
+
+	if _, err := cmd.CheckMailmap([]string{"a <a@example.com>"}); !errors.Is(err, RunningCommandError) {
+		t.Errorf("expected RunningCommandError for a short answer, got %v", err)
+	}
+	if mapped, err := (Commands{Cmd: runner}).CheckMailmap(nil); mapped != nil || err != nil {
+		t.Errorf("expected nothing to map, got %q, %v", mapped, err)
+	}
+}
+
+func TestCommands_GetTags(t *testing.T) {
+	cmd := Commands{Cmd: MockRunner{Output: "v1.0.0\nv1.1.0\n\nnightly"}}
+
+	tags, err := cmd.GetTags()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(tags, ",") != "v1.0.0,v1.1.0,nightly" {
+		t.Errorf("unexpected tags %q", tags)
+	}
+
+	cmd = Commands{Cmd: MockRunner{Err: RunningCommandError}}
+	if _, err := cmd.GetTags(); !errors.Is(err, RunningCommandError) {
+		t.Errorf("expected RunningCommandError, got %v", err)
+	}
+}
+
+func TestCommands_CommitFiles(t *testing.T) {
+	runner := &scriptedRunner{responses: map[string]scriptedResponse{"diff --cached": {output: "CHANGELOG.md"}}}
+	cmd := Commands{Cmd: runner}
+
+	if staged, err := cmd.HasStagedChanges(); err != nil || !staged {
+		t.Errorf("expected staged changes, got %t, %v", staged, err)
+	}
+	if err := cmd.CommitFiles("Release v1.0.0", []string{"CHANGELOG.md"}, []string{"entry.md"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, call := range []string{"add --all -- CHANGELOG.md", "rm --cached --quiet --ignore-unmatch -- entry.md", "commit --quiet --message Release v1.0.0"} {
+		if !runner.ran(call) {
+			t.Errorf("expected %q, got %q", call, runner.calls)
+		}
+	}
+}
+
+func TestCommandRunner_RunContext(t *testing.T) {
+	t.Run("Times out", func(t *testing.T) {
+		runner := CommandRunner{Timeout: 50 * time.Millisecond}
+		start := time.Now()
+		_, err := runner.Run(OS, "sleep", "5")
+		if !errors.Is(err, TimeoutError) || !errors.Is(err, RunningCommandError) {
+			t.Errorf("expected TimeoutError, got %v", err)
+		}
+		if time.Since(start) > 3*time.Second {
+			t.Errorf("expected the command to be killed, took %s", time.Since(start))
+		}
+	})
+
+	t.Run("Stops when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := CommandRunner{}.RunContext(ctx, OS, "sleep", "5")
+		if !errors.Is(err, context.Canceled) || errors.Is(err, TimeoutError) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("Stops Commands from New with DefaultContext", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		t.Cleanup(func() { DefaultContext = nil })
+		DefaultContext = ctx
+
+		cmd := New()
+		if cmd.Context != ctx {
+			t.Fatal("expected New to use DefaultContext")
+		}
+		if _, err := cmd.GetUsername(); !errors.Is(err, NoUsernameFoundError) {
+			t.Errorf("expected the username lookups to be stopped, got %v", err)
+		}
+	})
+
+	t.Run("Disables git prompts", func(t *testing.T) {
+		output, err := CommandRunner{}.Run(GIT, "-c", "alias.prompt=!echo $GIT_TERMINAL_PROMPT", "prompt")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if output != "0" {
+			t.Errorf("expected GIT_TERMINAL_PROMPT=0, got %q", output)
+		}
+	})
+
+	t.Run("Reports directories outside a repository", func(t *testing.T) {
+		dir := t.TempDir()
+		cwd, _ := os.Getwd()
+		t.Cleanup(func() { os.Chdir(cwd) })
+		os.Chdir(dir)
+		t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+
+		_, err := CommandRunner{}.Run(GIT, "rev-parse", "--show-toplevel")
+		if !errors.Is(err, NotARepositoryError) {
+			t.Errorf("expected NotARepositoryError, got %v", err)
+		}
+	})
+
+	t.Run("Reports missing git", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		_, err := CommandRunner{}.Run(GIT, "--version")
+		if !errors.Is(err, GitNotFoundError) {
+			t.Errorf("expected GitNotFoundError, got %v", err)
+		}
+	})
+}
+
+func TestCommandError_Auth(t *testing.T) {
+	outputs := []string{
+		"fatal: Authentication failed for 'https://example.com/repo.git/'",
+		"git@example.com: Permission denied (publickey).\nfatal: Could not read from remote repository.",
+		"fatal: could not read Username for 'https://example.com': terminal prompts disabled",
+	}
+	for _, stderr := range outputs {
+		err := commandError(GIT, "git", []string{"fetch", "origin"}, errors.New("exit status 128"), stderr, context.Background(), context.Background(), time.Second)
+		if !errors.Is(err, AuthError) || !errors.Is(err, RunningCommandError) {
+			t.Errorf("expected AuthError for %q, got %v", stderr, err)
+		}
+		if Reason(err) != AuthError.Error() {
+			t.Errorf("expected the reason to name the auth failure, got %q", Reason(err))
+		}
+	}
+
+	err := commandError(GIT, "git", []string{"fetch", "origin"}, errors.New("exit status 1"), "fatal: couldn't find remote ref\nmore", context.Background(), context.Background(), time.Second)
+	if errors.Is(err, AuthError) || Reason(err) != "fatal: couldn't find remote ref" {
+		t.Errorf("expected an untyped error with its first line as reason, got %v (%q)", err, Reason(err))
+	}
+}
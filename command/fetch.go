@@ -0,0 +1,104 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// FetchPolicy decides when Commands fetch from the remote.
+type FetchPolicy string
+
+const (
+	// FetchAlways fetches before every command that reads remote branches.
+	FetchAlways FetchPolicy = "always"
+	// FetchOnce fetches the first time remote branches are needed in a run.
+	FetchOnce FetchPolicy = "once"
+	// FetchNever works offline from the refs already in the repository.
+	FetchNever FetchPolicy = "never"
+)
+
+// FetchPolicies lists every fetch policy.
+var FetchPolicies = []FetchPolicy{FetchAlways, FetchOnce, FetchNever}
+
+// Predefined errors
+var (
+	UnknownFetchPolicyError = errors.New("unknown fetch policy")
+)
+
+// ParseFetchPolicy reads a fetch policy name. "offline" is accepted for
+// FetchNever.
+func ParseFetchPolicy(name string) (FetchPolicy, error) {
+	if strings.EqualFold(name, "offline") {
+		return FetchNever, nil
+	}
+	for _, policy := range FetchPolicies {
+		if strings.EqualFold(name, string(policy)) {
+			return policy, nil
+		}
+	}
+	return "", fmt.Errorf("%w %q, expected always, once or never", UnknownFetchPolicyError, name)
+}
+
+// Fetcher runs git fetch as its policy allows and remembers the outcome,
+// so callers can tell when remote data may be stale.
+type Fetcher struct {
+	Policy FetchPolicy
+
+	mu        sync.Mutex
+	attempted bool
+	err       error
+}
+
+// DefaultFetcher is shared by the Commands returned from New, so a run
+// fetches once however many commands need the remote.
+var DefaultFetcher = &Fetcher{Policy: FetchOnce}
+
+// SetPolicy changes the policy and forgets earlier fetches.
+func (f *Fetcher) SetPolicy(policy FetchPolicy) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Policy = policy
+	f.attempted = false
+	f.err = nil
+}
+
+// Fetch fetches remote unless the policy says otherwise. A nil Fetcher
+// always fetches.
+func (f *Fetcher) Fetch(ctx context.Context, c Commander, remote string) {
+	if f == nil {
+		_, _ = runContext(ctx, c, GIT, "fetch", remote)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch f.Policy {
+	case FetchNever:
+		return
+	case FetchOnce:
+		if f.attempted {
+			return
+		}
+	}
+	f.attempted = true
+	_, f.err = runContext(ctx, c, GIT, "fetch", remote)
+}
+
+// Stale explains why remote branches and commits may be out of date, empty
+// when the last fetch succeeded or none was needed yet.
+func (f *Fetcher) Stale() string {
+	if f == nil {
+		return ""
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case f.Policy == FetchNever:
+		return "fetching is disabled, using the remote branches from the last fetch"
+	case f.err != nil:
+		return fmt.Sprintf("could not fetch from the remote, using local refs (%s)", Reason(f.err))
+	}
+	return ""
+}
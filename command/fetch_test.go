@@ -0,0 +1,148 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type scriptedResponse struct {
+	output string
+	err    error
+}
+
+// scriptedRunner answers git commands by the longest matching prefix of
+// their arguments and records every call.
+type scriptedRunner struct {
+	responses map[string]scriptedResponse
+	calls     []string
+}
+
+func (s *scriptedRunner) Run(ct CommandType, args ...string) (string, error) {
+	call := strings.Join(args, " ")
+	s.calls = append(s.calls, call)
+	// The longest matching prefix wins.
+	var match string
+	for prefix := range s.responses {
+		if strings.HasPrefix(call, prefix) && len(prefix) > len(match) {
+			match = prefix
+		}
+	}
+	response := s.responses[match]
+	return response.output, response.err
+}
+
+func (s *scriptedRunner) ran(prefix string) bool {
+	return s.count(prefix) > 0
+}
+
+func (s *scriptedRunner) count(prefix string) int {
+	n := 0
+	for _, call := range s.calls {
+		if strings.HasPrefix(call, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestParseFetchPolicy(t *testing.T) {
+	tests := map[string]FetchPolicy{"always": FetchAlways, "Once": FetchOnce, "never": FetchNever, "offline": FetchNever}
+	for name, want := range tests {
+		if got, err := ParseFetchPolicy(name); err != nil || got != want {
+			t.Errorf("ParseFetchPolicy(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseFetchPolicy("sometimes"); !errors.Is(err, UnknownFetchPolicyError) {
+		t.Errorf("expected UnknownFetchPolicyError, got %v", err)
+	}
+}
+
+func TestFetcher_Policies(t *testing.T) {
+	tests := []struct {
+		policy  FetchPolicy
+		fetches int
+	}{
+		{FetchAlways, 2},
+		{FetchOnce, 1},
+		{FetchNever, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			runner := &scriptedRunner{responses: map[string]scriptedResponse{"branch -r": {output: "origin/main"}}}
+			cmd := Commands{Cmd: runner, Fetch: &Fetcher{Policy: tt.policy}}
+
+			cmd.GetBranches()
+			cmd.GetCommitLogBetweenBranches("main", "feature")
+
+			if got := runner.count("fetch"); got != tt.fetches {
+				t.Errorf("expected %d fetches, got %d: %q", tt.fetches, got, runner.calls)
+			}
+		})
+	}
+
+	runner := &scriptedRunner{}
+	Commands{Cmd: runner}.GetBranches()
+	if runner.count("fetch") != 1 {
+		t.Errorf("expected a nil fetcher to always fetch, got %q", runner.calls)
+	}
+}
+
+func TestFetcher_Stale(t *testing.T) {
+	fetcher := &Fetcher{Policy: FetchOnce}
+	if fetcher.Stale() != "" {
+		t.Error("expected no warning before fetching")
+	}
+
+	failing := &scriptedRunner{responses: map[string]scriptedResponse{
+		"fetch": {err: errors.New("error running supplied command: fatal: unable to access\nmore")},
+	}}
+	fetcher.Fetch(context.Background(), failing, "origin")
+	if stale := fetcher.Stale(); !strings.HasSuffix(stale, "(fatal: unable to access)") {
+		t.Errorf("expected the first line of the fetch error, got %q", stale)
+	}
+
+	fetcher.SetPolicy(FetchNever)
+	if !strings.Contains(fetcher.Stale(), "disabled") {
+		t.Errorf("expected an offline warning, got %q", fetcher.Stale())
+	}
+
+	fetcher.SetPolicy(FetchAlways)
+	fetcher.Fetch(nil, &scriptedRunner{}, "origin")
+	if fetcher.Stale() != "" {
+		t.Errorf("expected no warning after a successful fetch, got %q", fetcher.Stale())
+	}
+}
+
+func TestCommands_GetBranches_LocalFallback(t *testing.T) {
+	runner := &scriptedRunner{responses: map[string]scriptedResponse{
+		"branch -r": {output: ""},
+		"branch":    {output: "main\nfeature/login"},
+	}}
+	cmd := Commands{Cmd: runner, Fetch: &Fetcher{Policy: FetchNever}}
+
+	branches, err := cmd.GetBranches()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(branches, ",") != "main,feature/login" {
+		t.Errorf("expected the local branches, got %q", branches)
+	}
+}
+
+func TestCommands_GetCommitLogBetweenBranches_LocalTarget(t *testing.T) {
+	runner := &scriptedRunner{responses: map[string]scriptedResponse{
+		"rev-parse --verify --quiet refs/remotes/": {err: RunningCommandError},
+		"log": {output: ""},
+	}}
+	cmd := Commands{Cmd: runner, Fetch: &Fetcher{Policy: FetchNever}}
+
+	if _, err := cmd.GetCommitLogBetweenBranches("main", "feature"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !runner.ran("log main..feature") {
+		t.Errorf("expected the local target branch to be compared, got %q", runner.calls)
+	}
+}
@@ -0,0 +1,125 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommands_DetectRemote(t *testing.T) {
+	tests := []struct {
+		name           string
+		responses      map[string]scriptedResponse
+		wantRemote     string
+		wantCandidates string
+	}{
+		{
+			name: "tracking remote",
+			responses: map[string]scriptedResponse{
+				"rev-parse --abbrev-ref HEAD":        {output: "feature"},
+				"config --get branch.feature.remote": {output: "upstream"},
+				"remote":                             {output: "origin\nupstream"},
+			},
+			wantRemote: "upstream",
+		},
+		{
+			name: "local upstream",
+			responses: map[string]scriptedResponse{
+				"rev-parse --abbrev-ref HEAD":        {output: "feature"},
+				"config --get branch.feature.remote": {output: "."},
+				"remote":                             {output: "fork"},
+			},
+			wantRemote: "fork",
+		},
+		{
+			name:       "no remotes",
+			responses:  map[string]scriptedResponse{"rev-parse --abbrev-ref HEAD": {output: "feature"}},
+			wantRemote: "origin",
+		},
+		{
+			name: "several remotes",
+			responses: map[string]scriptedResponse{
+				"rev-parse --abbrev-ref HEAD": {output: "feature"},
+				"remote":                      {output: "fork\norigin\nupstream"},
+			},
+			wantRemote:     "origin",
+			wantCandidates: "fork,origin,upstream",
+		},
+		{
+			name: "several remotes without origin",
+			responses: map[string]scriptedResponse{
+				"rev-parse --abbrev-ref HEAD": {output: "feature"},
+				"remote":                      {output: "fork\nupstream"},
+			},
+			wantRemote:     "fork",
+			wantCandidates: "fork,upstream",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := Commands{Cmd: &scriptedRunner{responses: tt.responses}}
+			remote, candidates := cmd.DetectRemote()
+			if remote != tt.wantRemote || strings.Join(candidates, ",") != tt.wantCandidates {
+				t.Errorf("got %q %q, want %q %q", remote, candidates, tt.wantRemote, tt.wantCandidates)
+			}
+		})
+	}
+}
+
+func TestCommands_Remote(t *testing.T) {
+	runner := &scriptedRunner{responses: map[string]scriptedResponse{
+		"config --get remote.upstream.url": {output: "git@github.com:acme/app.git"},
+		"branch -r":                        {output: "origin/HEAD\norigin\norigin/my-fix\nupstream/main\nupstream/release/1.x"},
+		"rev-parse --verify --quiet refs/remotes/upstream/main": {},
+	}}
+	cmd := Commands{Cmd: runner, Remote: "upstream", Fetch: &Fetcher{Policy: FetchAlways}}
+
+	url, err := cmd.GetCommitHttpUrlPrefixFromRemoteUrl()
+	if err != nil || url != "https://github.com/acme/app/commit/" {
+		t.Errorf("expected the upstream commit links, got %q, %v", url, err)
+	}
+
+	branches, err := cmd.GetBranches()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(branches, ",") != "main,release/1.x,origin/my-fix" {
+		t.Errorf("expected the remote's branches first, got %q", branches)
+	}
+	if !runner.ran("fetch upstream") {
+		t.Errorf("expected the remote to be fetched, got %q", runner.calls)
+	}
+
+	runner.calls = nil
+	cmd.GetCommitLogBetweenBranches("main", "feature")
+	if !runner.ran("log upstream/main..feature") {
+		t.Errorf("expected the remote's target branch, got %q", runner.calls)
+	}
+}
+
+func TestCommands_TargetRef(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		target   string
+		want     string
+	}{
+		{"remote branch", []string{"refs/remotes/origin/main"}, "main", "origin/main"},
+		{"branch with its remote", []string{"refs/remotes/upstream/main"}, "upstream/main", "upstream/main"},
+		{"local branch", []string{"refs/heads/main"}, "main", "main"},
+		{"unknown", nil, "main", "origin/main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := map[string]scriptedResponse{"rev-parse --verify": {err: RunningCommandError}}
+			for _, ref := range tt.existing {
+				responses["rev-parse --verify --quiet "+ref] = scriptedResponse{}
+			}
+			cmd := Commands{Cmd: &scriptedRunner{responses: responses}, Remote: "origin"}
+			if got := cmd.targetRef(tt.target); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package command
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// DefaultBaseBranches are the BaseBranches of the Commands returned from
+// New, replaced once the repository's configuration is loaded.
+var DefaultBaseBranches = []string{"main", "master", "develop", "release/*"}
+
+// GetDefaultBranch returns the branch the remote's HEAD points at, empty
+// when the clone does not know it.
+func (c Commands) GetDefaultBranch() string {
+	remote := c.remote()
+	head, err := c.run(GIT, "symbolic-ref", "--quiet", "--short", "refs/remotes/"+remote+"/HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(head, remote+"/")
+}
+
+// DetectTargetBranch guesses the branch the current one will be merged
+// into, with the reason for the guess:
+//
+//  1. the branch it tracks, when that is not its namesake on the remote;
+//  2. the base branch it forked from most recently, among the branches
+//     matching BaseBranches and the remote's default branch;
+//  3. the remote's default branch.
+//
+// Both are empty when there is nothing to go on.
+func (c Commands) DetectTargetBranch() (branch, reason string) {
+	current, err := c.GetCurrentBranch()
+	if err != nil {
+		return "", ""
+	}
+	if merge, _ := c.run(GIT, "config", "--get", "branch."+current+".merge"); merge != "" {
+		if tracked := strings.TrimPrefix(merge, "refs/heads/"); tracked != current {
+			return tracked, "tracked by " + current
+		}
+	}
+
+	defaultBranch := c.GetDefaultBranch()
+	if base, ahead, ok := c.nearestBase(current, defaultBranch); ok {
+		return base, fmt.Sprintf("closest base branch, forked %d commit%s ago", ahead, plural(ahead))
+	}
+	if defaultBranch != "" {
+		return defaultBranch, "default branch of " + c.remote()
+	}
+	return "", ""
+}
+
+// IsBaseBranch reports whether branch matches BaseBranches or is the
+// remote's default branch, a branch others merge into rather than one a
+// change is made on.
+func (c Commands) IsBaseBranch(branch string) bool {
+	if branch == c.GetDefaultBranch() {
+		return true
+	}
+	for _, pattern := range c.baseBranches() {
+		if matched, _ := path.Match(pattern, branch); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// nearestBase finds the candidate base branch with the fewest commits
+// between its merge base with current and current. Ties go to the default
+// branch, then to the order of BaseBranches.
+func (c Commands) nearestBase(current, defaultBranch string) (base string, ahead int, ok bool) {
+	branches, err := c.GetBranches()
+	if err != nil {
+		return "", 0, false
+	}
+	var candidates []string
+	if defaultBranch != "" {
+		candidates = append(candidates, defaultBranch)
+	}
+	for _, pattern := range c.baseBranches() {
+		for _, branch := range branches {
+			if matched, _ := path.Match(pattern, branch); matched && !containsString(candidates, branch) {
+				candidates = append(candidates, branch)
+			}
+		}
+	}
+
+	for _, candidate := range candidates {
+		if candidate == current {
+			continue
+		}
+		mergeBase, err := c.run(GIT, "merge-base", c.targetRef(candidate), current)
+		if err != nil || mergeBase == "" {
+			continue
+		}
+		count, err := c.run(GIT, "rev-list", "--count", mergeBase+".."+current)
+		if err != nil {
+			continue
+		}
+		n, err := strconv.Atoi(count)
+		if err != nil {
+			continue
+		}
+		if !ok || n < ahead {
+			base, ahead, ok = candidate, n, true
+		}
+	}
+	return base, ahead, ok
+}
+
+func (c Commands) baseBranches() []string {
+	if c.BaseBranches != nil {
+		return c.BaseBranches
+	}
+	return DefaultBaseBranches
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
@@ -0,0 +1,107 @@
+package command
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCommands_DetectTargetBranch(t *testing.T) {
+	base := map[string]scriptedResponse{
+		"rev-parse --abbrev-ref HEAD": {output: "feature"},
+		"remote":                      {output: "origin"},
+		"branch -r":                   {output: "origin/HEAD\norigin/main\norigin/develop\norigin/release/1.0\norigin/feature"},
+		"symbolic-ref --quiet --short refs/remotes/origin/HEAD": {output: "origin/main"},
+		"merge-base origin/main feature":                        {output: "aaa"},
+		"merge-base origin/develop feature":                     {output: "bbb"},
+		"merge-base origin/release/1.0 feature":                 {output: "ccc"},
+		"rev-list --count aaa..feature":                         {output: "5"},
+		"rev-list --count bbb..feature":                         {output: "2"},
+		"rev-list --count ccc..feature":                         {output: "2"},
+	}
+	with := func(overrides map[string]scriptedResponse) map[string]scriptedResponse {
+		responses := make(map[string]scriptedResponse)
+		for prefix, response := range base {
+			responses[prefix] = response
+		}
+		for prefix, response := range overrides {
+			responses[prefix] = response
+		}
+		return responses
+	}
+	failed := scriptedResponse{err: errors.New("failed")}
+
+	tests := []struct {
+		name       string
+		responses  map[string]scriptedResponse
+		wantBranch string
+		wantReason string
+	}{
+		{
+			name:       "tracked branch",
+			responses:  with(map[string]scriptedResponse{"config --get branch.feature.merge": {output: "refs/heads/develop"}}),
+			wantBranch: "develop",
+			wantReason: "tracked by feature",
+		},
+		{
+			name:       "nearest base when tracking its namesake",
+			responses:  with(map[string]scriptedResponse{"config --get branch.feature.merge": {output: "refs/heads/feature"}}),
+			wantBranch: "develop",
+			wantReason: "closest base branch, forked 2 commits ago",
+		},
+		{
+			name:       "default branch wins ties",
+			responses:  with(map[string]scriptedResponse{"rev-list --count aaa..feature": {output: "2"}}),
+			wantBranch: "main",
+			wantReason: "closest base branch, forked 2 commits ago",
+		},
+		{
+			name: "default branch without merge bases",
+			responses: with(map[string]scriptedResponse{
+				"merge-base origin/main feature":        failed,
+				"merge-base origin/develop feature":     failed,
+				"merge-base origin/release/1.0 feature": failed,
+			}),
+			wantBranch: "main",
+			wantReason: "default branch of origin",
+		},
+		{
+			name: "nothing to go on",
+			responses: with(map[string]scriptedResponse{
+				"merge-base origin/main feature":                        failed,
+				"merge-base origin/develop feature":                     failed,
+				"merge-base origin/release/1.0 feature":                 failed,
+				"symbolic-ref --quiet --short refs/remotes/origin/HEAD": failed,
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := Commands{Cmd: &scriptedRunner{responses: tt.responses}, BaseBranches: []string{"main", "develop", "release/*"}}
+			branch, reason := cmd.DetectTargetBranch()
+			if branch != tt.wantBranch || reason != tt.wantReason {
+				t.Errorf("got %q (%s), want %q (%s)", branch, reason, tt.wantBranch, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestCommands_IsBaseBranch(t *testing.T) {
+	runner := &scriptedRunner{responses: map[string]scriptedResponse{
+		"remote": {output: "origin"},
+		"symbolic-ref --quiet --short refs/remotes/origin/HEAD": {output: "origin/trunk"},
+	}}
+	cmd := Commands{Cmd: runner, BaseBranches: []string{"main", "release/*"}}
+
+	for branch, want := range map[string]bool{
+		"trunk":        true,
+		"main":         true,
+		"release/2.0":  true,
+		"feature/main": false,
+		"master":       false,
+	} {
+		if got := cmd.IsBaseBranch(branch); got != want {
+			t.Errorf("IsBaseBranch(%q) = %v, want %v", branch, got, want)
+		}
+	}
+}
@@ -0,0 +1,595 @@
+// Package config loads the per-repository .changelog.yaml (or .json) file
+// that customises change types, checklist items, asked sections and output
+// locations.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/abirhasanmubin/changelog-go/changelog"
+	"github.com/abirhasanmubin/changelog-go/check"
+	"github.com/abirhasanmubin/changelog-go/command"
+	"github.com/abirhasanmubin/changelog-go/forge"
+	"github.com/abirhasanmubin/changelog-go/release"
+)
+
+// FileNames are the config files looked up at the repository root, in
+// order of preference.
+var FileNames = []string{".changelog.yaml", ".changelog.yml", ".changelog.json"}
+
+// Optional sections the wizard can ask for, in their default order.
+const (
+	SectionMotivation   = "motivation"
+	SectionDescription  = "description"
+	SectionTodos        = "todos"
+	SectionModelChanges = "model_changes"
+	SectionTesting      = "testing"
+)
+
+var DefaultSections = []string{SectionMotivation, SectionDescription, SectionTodos, SectionModelChanges, SectionTesting}
+
+// Predefined errors
+var (
+	InvalidConfigError = errors.New("invalid config")
+)
+
+type Output struct {
+	// Dir is where entries are saved, relative to the repository root.
+	Dir string `json:"dir" yaml:"dir"`
+	// FrontMatter is the format of the block written above each entry:
+	// yaml, json or none.
+	FrontMatter string `json:"front_matter" yaml:"front_matter"`
+}
+
+type Release struct {
+	// File is the changelog releases are written to, relative to the
+	// repository root.
+	File string `json:"file" yaml:"file"`
+	// Components is how the entries of components are released: grouped
+	// in File, or split into a changelog in each component's directory.
+	Components string `json:"components" yaml:"components"`
+}
+
+type Git struct {
+	// Fetch is when the remote is fetched: always, once per run, or never
+	// to work offline.
+	Fetch command.FetchPolicy `json:"fetch" yaml:"fetch"`
+	// Remote is the remote target branches are compared against and commit
+	// links point at, such as upstream in a fork. Empty detects it.
+	Remote string `json:"remote" yaml:"remote"`
+	// BaseBranches are the branches, or globs such as release/*, a branch
+	// may have forked from, for detecting its target branch.
+	BaseBranches []string `json:"base_branches" yaml:"base_branches"`
+}
+
+type Files struct {
+	// Enabled adds a Files changed section listing the files the branch
+	// changed.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// CollapseAfter shortens the section when more files changed. Zero
+	// never collapses.
+	CollapseAfter int `json:"collapse_after" yaml:"collapse_after"`
+}
+
+type Contributors struct {
+	// Enabled credits the commit authors and co-authors in a Contributors
+	// line.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Handles map email addresses or names, after the mailmap, to forge
+	// handles mentioned instead of the name.
+	Handles map[string]string `json:"handles" yaml:"handles"`
+}
+
+type Check struct {
+	// TestingRequiredFor are the change types whose entries need testing
+	// instructions. Nil uses the configured ones of
+	// check.DefaultTestingChangeTypes.
+	TestingRequiredFor []string `json:"testing_required_for" yaml:"testing_required_for"`
+}
+
+// Component is a part of a monorepo, such as a service, that entries can
+// belong to.
+type Component struct {
+	Name string `json:"name" yaml:"name"`
+	// Paths are globs relative to the repository root, such as
+	// services/api/**, matching the files the component owns. A path
+	// without wildcards matches everything below it.
+	Paths []string `json:"paths" yaml:"paths"`
+	// Dir holds the component's changelog on split releases. Empty uses
+	// the directory the first path starts with.
+	Dir string `json:"dir" yaml:"dir"`
+}
+
+// DefaultCollapseFilesAfter is how many changed files are listed before
+// the Files changed section collapses.
+const DefaultCollapseFilesAfter = 20
+
+// Ticket describes where the keys of an issue tracker appear and link to.
+type Ticket struct {
+	// Type is jira, linear or github, giving the pattern when it is not
+	// set.
+	Type string `json:"type" yaml:"type"`
+	// Pattern is a regular expression matching keys.
+	Pattern string `json:"pattern" yaml:"pattern"`
+	// URL links a key: {key} is replaced with the key and {id} with the
+	// pattern's first group. GitHub issues default to the remote's issues.
+	URL string `json:"url" yaml:"url"`
+}
+
+// Forge tells how a self-hosted git server builds its web links.
+type Forge struct {
+	// Host is the host name in remote URLs, with the port for ssh remotes
+	// on a non-standard one, such as git.example.com:7999.
+	Host string `json:"host" yaml:"host"`
+	// Type is one of github, gitlab, bitbucket, bitbucket-server,
+	// azure-devops or gitea.
+	Type string `json:"type" yaml:"type"`
+	// URL is the web address when it differs from https://<host>.
+	URL string `json:"url" yaml:"url"`
+}
+
+type Config struct {
+	ChangeTypes  []string            `json:"change_types" yaml:"change_types"`
+	Checklist    changelog.Checklist `json:"checklist" yaml:"checklist"`
+	Sections     []string            `json:"sections" yaml:"sections"`
+	Output       Output              `json:"output" yaml:"output"`
+	Release      Release             `json:"release" yaml:"release"`
+	Git          Git                 `json:"git" yaml:"git"`
+	Forges       []Forge             `json:"forges" yaml:"forges"`
+	Files        Files               `json:"files" yaml:"files"`
+	Tickets      []Ticket            `json:"tickets" yaml:"tickets"`
+	Contributors Contributors        `json:"contributors" yaml:"contributors"`
+	Components   []Component         `json:"components" yaml:"components"`
+	Check        Check               `json:"check" yaml:"check"`
+	// Templates maps template names (markdown, pr) to files, relative to
+	// the repository root, that replace the built-in templates.
+	Templates map[string]string `json:"templates" yaml:"templates"`
+
+	// Root is the repository root the config was loaded for and Path the
+	// file it was read from, empty when the defaults are used.
+	Root string `json:"-" yaml:"-"`
+	Path string `json:"-" yaml:"-"`
+}
+
+// Default returns the built-in configuration for root.
+func Default(root string) Config {
+	return Config{
+		ChangeTypes:  append([]string(nil), changelog.DefaultChangeTypes...),
+		Checklist:    changelog.DefaultChecklist(),
+		Sections:     append([]string(nil), DefaultSections...),
+		Output:       Output{Dir: changelog.DefaultDir, FrontMatter: changelog.FrontMatterYAML},
+		Release:      Release{File: release.DefaultFile, Components: release.ComponentsGrouped},
+		Git:          Git{Fetch: command.FetchOnce, BaseBranches: append([]string(nil), command.DefaultBaseBranches...)},
+		Files:        Files{Enabled: true, CollapseAfter: DefaultCollapseFilesAfter},
+		Contributors: Contributors{Enabled: true},
+		Root:         root,
+	}
+}
+
+// Load discovers the repository root from the working directory and loads
+// its config. Outside a repository the working directory is used as root.
+func Load() (Config, error) {
+	cmd := command.New()
+	root, err := cmd.GetRepositoryRoot()
+	if err != nil || root == "" {
+		if root, err = os.Getwd(); err != nil {
+			return Config{}, err
+		}
+	}
+	return LoadFrom(root)
+}
+
+// LoadFrom loads the first config file found in root, falling back to the
+// defaults when there is none. Keys missing from the file keep their
+// default values.
+func LoadFrom(root string) (Config, error) {
+	cfg := Default(root)
+	for _, name := range FileNames {
+		path := filepath.Join(root, name)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return cfg, err
+		}
+		if err := decode(path, data, &cfg); err != nil {
+			return cfg, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		cfg.Path = path
+		break
+	}
+
+	if err := cfg.Validate(); err != nil {
+		if cfg.Path != "" {
+			return cfg, fmt.Errorf("%s: %w", cfg.Path, err)
+		}
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+func decode(path string, data []byte, cfg *Config) error {
+	if strings.HasSuffix(path, ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		return decoder.Decode(cfg)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// Validate reports every problem with the config at once.
+func (c *Config) Validate() error {
+	var problems []string
+
+	if len(c.ChangeTypes) == 0 {
+		problems = append(problems, "change_types must not be empty")
+	}
+	seen := make(map[string]bool)
+	for _, changeType := range c.ChangeTypes {
+		key := strings.ToLower(strings.TrimSpace(changeType))
+		switch {
+		case key == "":
+			problems = append(problems, "change_types must not contain empty names")
+		case strings.Contains(changeType, ":"):
+			problems = append(problems, fmt.Sprintf("change type %q must not contain ':'", changeType))
+		case seen[key]:
+			problems = append(problems, fmt.Sprintf("duplicate change type %q", changeType))
+		}
+		seen[key] = true
+	}
+
+	seen = make(map[string]bool)
+	for i, item := range c.Checklist {
+		if strings.TrimSpace(item.Text) == "" {
+			// The built-in items may omit their text.
+			item.Text = defaultChecklistText(item.ID)
+			c.Checklist[i].Text = item.Text
+		}
+		switch {
+		case strings.TrimSpace(item.ID) == "":
+			problems = append(problems, "checklist items need an id")
+		case seen[item.ID]:
+			problems = append(problems, fmt.Sprintf("duplicate checklist item %q", item.ID))
+		case item.Text == "":
+			problems = append(problems, fmt.Sprintf("checklist item %q needs a text", item.ID))
+		case item.Checked:
+			problems = append(problems, fmt.Sprintf("checklist item %q cannot be pre-checked, use default", item.ID))
+		}
+		for _, pattern := range item.RequiredFor {
+			if _, err := path.Match(pattern, ""); err != nil {
+				problems = append(problems, fmt.Sprintf("checklist item %q has invalid required_for pattern %q", item.ID, pattern))
+			}
+		}
+		seen[item.ID] = true
+	}
+
+	seen = make(map[string]bool)
+	for _, section := range c.Sections {
+		if !contains(DefaultSections, section) {
+			problems = append(problems, fmt.Sprintf("unknown section %q, expected one of %s", section, strings.Join(DefaultSections, ", ")))
+		} else if seen[section] {
+			problems = append(problems, fmt.Sprintf("duplicate section %q", section))
+		}
+		seen[section] = true
+	}
+
+	names := make([]string, 0, len(c.Templates))
+	for name := range c.Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		file := c.Templates[name]
+		if !contains(changelog.TemplateNames, name) {
+			problems = append(problems, fmt.Sprintf("unknown template %q, expected one of %s", name, strings.Join(changelog.TemplateNames, ", ")))
+		} else if strings.TrimSpace(file) == "" {
+			problems = append(problems, fmt.Sprintf("template %q needs a file", name))
+		}
+	}
+
+	if strings.TrimSpace(c.Output.Dir) == "" {
+		c.Output.Dir = changelog.DefaultDir
+	}
+	if c.Output.FrontMatter == "" {
+		c.Output.FrontMatter = changelog.FrontMatterYAML
+	} else if !contains(changelog.FrontMatterFormats, c.Output.FrontMatter) {
+		problems = append(problems, fmt.Sprintf("unknown front_matter %q, expected one of %s", c.Output.FrontMatter, strings.Join(changelog.FrontMatterFormats, ", ")))
+	}
+	if c.Git.Fetch == "" {
+		c.Git.Fetch = command.FetchOnce
+	} else if policy, err := command.ParseFetchPolicy(string(c.Git.Fetch)); err != nil {
+		problems = append(problems, err.Error())
+	} else {
+		c.Git.Fetch = policy
+	}
+	for _, pattern := range c.Git.BaseBranches {
+		if _, err := path.Match(pattern, ""); err != nil || strings.TrimSpace(pattern) == "" {
+			problems = append(problems, fmt.Sprintf("invalid git.base_branches pattern %q", pattern))
+		}
+	}
+	for i := range c.Tickets {
+		ticket := &c.Tickets[i]
+		if ticket.Pattern == "" {
+			ticket.Pattern = changelog.TicketTypes[ticket.Type]
+		}
+		if _, known := changelog.TicketTypes[ticket.Type]; ticket.Type != "" && !known {
+			problems = append(problems, fmt.Sprintf("unknown ticket type %q, expected jira, linear or github", ticket.Type))
+		} else if ticket.Pattern == "" {
+			problems = append(problems, fmt.Sprintf("ticket %d needs a type or a pattern", i+1))
+		} else if _, err := changelog.NewTicketPattern(ticket.Pattern, ticket.URL); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	for _, f := range c.Forges {
+		if strings.TrimSpace(f.Host) == "" {
+			problems = append(problems, "forges need a host")
+		} else if _, err := forge.ParseKind(f.Type); err != nil {
+			problems = append(problems, fmt.Sprintf("forge %q: %v", f.Host, err))
+		}
+	}
+	if c.Files.CollapseAfter < 0 {
+		problems = append(problems, "files.collapse_after must not be negative")
+	}
+	var unhandled []string
+	for key, handle := range c.Contributors.Handles {
+		if strings.TrimSpace(strings.TrimPrefix(handle, "@")) == "" {
+			unhandled = append(unhandled, key)
+		}
+	}
+	sort.Strings(unhandled)
+	for _, key := range unhandled {
+		problems = append(problems, fmt.Sprintf("contributors.handles: %q needs a handle", key))
+	}
+	if strings.TrimSpace(c.Release.File) == "" {
+		c.Release.File = release.DefaultFile
+	}
+	if c.Release.Components == "" {
+		c.Release.Components = release.ComponentsGrouped
+	} else if !contains(release.ComponentModes, c.Release.Components) {
+		problems = append(problems, fmt.Sprintf("unknown release.components %q, expected one of %s", c.Release.Components, strings.Join(release.ComponentModes, ", ")))
+	}
+	problems = append(problems, c.validateComponents()...)
+	for _, changeType := range c.Check.TestingRequiredFor {
+		if !contains(c.ChangeTypes, changeType) {
+			problems = append(problems, fmt.Sprintf("check.testing_required_for: unknown change type %q", changeType))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", InvalidConfigError, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// validateComponents reports unnamed, duplicate and pathless components,
+// and on split releases components sharing or lacking a directory.
+func (c Config) validateComponents() []string {
+	var problems []string
+	names := make(map[string]bool)
+	dirs := make(map[string]string)
+	for i, component := range c.Components {
+		name := strings.TrimSpace(component.Name)
+		switch {
+		case name == "":
+			problems = append(problems, fmt.Sprintf("component %d needs a name", i+1))
+			continue
+		case names[strings.ToLower(name)]:
+			problems = append(problems, fmt.Sprintf("duplicate component %q", name))
+		}
+		names[strings.ToLower(name)] = true
+
+		if len(component.Paths) == 0 {
+			problems = append(problems, fmt.Sprintf("component %q needs paths", name))
+		}
+		for _, pattern := range component.Paths {
+			if _, err := path.Match(pattern, ""); err != nil || strings.TrimSpace(pattern) == "" {
+				problems = append(problems, fmt.Sprintf("component %q has invalid path %q", name, pattern))
+			}
+		}
+
+		if c.Release.Components != release.ComponentsSplit {
+			continue
+		}
+		dir := componentDir(component)
+		if dir == "" {
+			problems = append(problems, fmt.Sprintf("component %q needs a dir for split releases", name))
+		} else if other, ok := dirs[dir]; ok {
+			problems = append(problems, fmt.Sprintf("components %q and %q share the dir %q", other, name, dir))
+		}
+		dirs[dir] = name
+	}
+	return problems
+}
+
+// componentDir is the component's directory: its dir, or the part of its
+// first path before any wildcard.
+func componentDir(component Component) string {
+	if strings.TrimSpace(component.Dir) != "" {
+		return filepath.Clean(component.Dir)
+	}
+	if len(component.Paths) == 0 {
+		return ""
+	}
+	var dir []string
+	for _, segment := range strings.Split(strings.Trim(component.Paths[0], "/"), "/") {
+		if strings.ContainsAny(segment, `*?[\`) {
+			break
+		}
+		dir = append(dir, segment)
+	}
+	if dir := path.Clean(strings.Join(dir, "/")); dir != "." {
+		return filepath.FromSlash(dir)
+	}
+	return ""
+}
+
+// RegisterForges makes remote URLs on the configured hosts use their forge.
+func (c Config) RegisterForges() {
+	for _, f := range c.Forges {
+		kind, err := forge.ParseKind(f.Type)
+		if err != nil {
+			continue
+		}
+		forge.Register(f.Host, forge.Host{Kind: kind, URL: f.URL})
+	}
+}
+
+// FileList is how entries list changed files.
+func (c Config) FileList() changelog.FileListOptions {
+	return changelog.FileListOptions{Skip: !c.Files.Enabled, CollapseAfter: c.Files.CollapseAfter}
+}
+
+// ContributorList is how entries credit contributors.
+func (c Config) ContributorList() changelog.ContributorOptions {
+	return changelog.ContributorOptions{Skip: !c.Contributors.Enabled, Handles: c.Contributors.Handles}
+}
+
+// ComponentPaths are the components entries are suggested from their
+// changed files.
+func (c Config) ComponentPaths() []changelog.Component {
+	var components []changelog.Component
+	for _, component := range c.Components {
+		components = append(components, changelog.Component{Name: component.Name, Paths: component.Paths})
+	}
+	return components
+}
+
+// ComponentNames lists the configured components in order.
+func (c Config) ComponentNames() []string {
+	var names []string
+	for _, component := range c.Components {
+		names = append(names, component.Name)
+	}
+	return names
+}
+
+// TicketPatterns compiles the configured ticket patterns. GitHub tickets
+// without a URL link to the issues of the repository's remote.
+func (c Config) TicketPatterns() []changelog.TicketPattern {
+	var patterns []changelog.TicketPattern
+	for _, ticket := range c.Tickets {
+		url := ticket.URL
+		if url == "" && ticket.Type == changelog.TicketGitHub {
+			if repo, err := command.New().GetRepository(); err == nil {
+				url = repo.IssueURL("{id}")
+			}
+		}
+		pattern, err := changelog.NewTicketPattern(ticket.Pattern, url)
+		if err != nil {
+			continue
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+// TestingChangeTypes are the change types whose entries need testing
+// instructions.
+func (c Config) TestingChangeTypes() []string {
+	if c.Check.TestingRequiredFor != nil {
+		return c.Check.TestingRequiredFor
+	}
+	types := []string{}
+	for _, changeType := range check.DefaultTestingChangeTypes {
+		if contains(c.ChangeTypes, changeType) {
+			types = append(types, changeType)
+		}
+	}
+	return types
+}
+
+// ExcludedPaths are the paths, relative to the repository root, that are
+// not part of a branch's change: the entry directory when it is inside the
+// repository.
+func (c Config) ExcludedPaths() []string {
+	dir, err := filepath.Rel(c.Root, c.EntryDir())
+	if err != nil || c.Root == "" || dir == "." || strings.HasPrefix(dir, "..") {
+		return nil
+	}
+	return []string{filepath.ToSlash(dir)}
+}
+
+// HasSection reports whether the wizard should ask for section.
+func (c Config) HasSection(section string) bool {
+	return contains(c.Sections, section)
+}
+
+// EntryDir returns the absolute directory entries are saved to.
+func (c Config) EntryDir() string {
+	if filepath.IsAbs(c.Output.Dir) {
+		return c.Output.Dir
+	}
+	return filepath.Join(c.Root, c.Output.Dir)
+}
+
+// LoadTemplates parses the configured template overrides on top of the
+// built-in templates.
+func (c Config) LoadTemplates() (*changelog.Templates, error) {
+	files := make(map[string]string, len(c.Templates))
+	for name, file := range c.Templates {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(c.Root, file)
+		}
+		files[name] = file
+	}
+	return changelog.LoadTemplates(files)
+}
+
+// ReleaseFile returns the absolute path of the changelog releases are
+// written to.
+func (c Config) ReleaseFile() string {
+	if filepath.IsAbs(c.Release.File) {
+		return c.Release.File
+	}
+	return filepath.Join(c.Root, c.Release.File)
+}
+
+// ReleaseComponents returns the components as releases see them, each
+// with a changelog named like the release file in its directory.
+func (c Config) ReleaseComponents() []release.Component {
+	var components []release.Component
+	for _, component := range c.Components {
+		released := release.Component{Name: component.Name}
+		if dir := componentDir(component); dir != "" {
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(c.Root, dir)
+			}
+			released.File = filepath.Join(dir, filepath.Base(c.Release.File))
+		}
+		components = append(components, released)
+	}
+	return components
+}
+
+func defaultChecklistText(id string) string {
+	if item := changelog.DefaultChecklist().Item(id); item != nil {
+		return item.Text
+	}
+	return ""
+}
+
+func contains(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,328 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/abirhasanmubin/changelog-go/changelog"
+	"github.com/abirhasanmubin/changelog-go/command"
+)
+
+func writeConfig(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func TestLoadFrom_Defaults(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := LoadFrom(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Path != "" {
+		t.Errorf("expected no config path, got %q", cfg.Path)
+	}
+	if !reflect.DeepEqual(cfg.ChangeTypes, changelog.DefaultChangeTypes) {
+		t.Errorf("expected default change types, got %v", cfg.ChangeTypes)
+	}
+	if !reflect.DeepEqual(cfg.Checklist, changelog.DefaultChecklist()) {
+		t.Errorf("expected default checklist, got %v", cfg.Checklist)
+	}
+	if !reflect.DeepEqual(cfg.Sections, DefaultSections) {
+		t.Errorf("expected default sections, got %v", cfg.Sections)
+	}
+	if cfg.EntryDir() != filepath.Join(dir, ".logs", ".changelog") {
+		t.Errorf("unexpected entry dir %q", cfg.EntryDir())
+	}
+	if cfg.ReleaseFile() != filepath.Join(dir, "CHANGELOG.md") {
+		t.Errorf("unexpected release file %q", cfg.ReleaseFile())
+	}
+	if cfg.Git.Fetch != command.FetchOnce {
+		t.Errorf("expected to fetch once by default, got %q", cfg.Git.Fetch)
+	}
+	if fileList := cfg.FileList(); fileList.Skip || fileList.CollapseAfter != DefaultCollapseFilesAfter {
+		t.Errorf("expected changed files to be listed by default, got %+v", fileList)
+	}
+	if cfg.ContributorList().Skip {
+		t.Error("expected contributors to be credited by default")
+	}
+}
+
+func TestLoadFrom_YAML(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, ".changelog.yaml", `
+change_types: [Fix, Feature, Other]
+checklist:
+  - id: self-review
+  - id: tests
+    text: Tests cover the change
+    default: true
+sections: [description, testing]
+output:
+  dir: docs/changes
+git:
+  fetch: offline
+  remote: upstream
+  base_branches: [trunk, stable/*]
+tickets:
+  - type: jira
+    url: https://jira.example.com/browse/{key}
+  - pattern: '\bENG-\d+\b'
+files:
+  enabled: false
+contributors:
+  handles:
+    jane@example.com: '@janedoe'
+forges:
+  - host: git.example.com
+    type: gitlab
+    url: https://gitlab.example.com
+`)
+
+	cfg, err := LoadFrom(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Path != filepath.Join(dir, ".changelog.yaml") {
+		t.Errorf("unexpected config path %q", cfg.Path)
+	}
+	if !reflect.DeepEqual(cfg.ChangeTypes, []string{"Fix", "Feature", "Other"}) {
+		t.Errorf("unexpected change types %v", cfg.ChangeTypes)
+	}
+	wantChecklist := changelog.Checklist{
+		{ID: "self-review", Text: "I have performed a self-review of my code"},
+		{ID: "tests", Text: "Tests cover the change", Default: true},
+	}
+	if !reflect.DeepEqual(cfg.Checklist, wantChecklist) {
+		t.Errorf("expected %v, got %v", wantChecklist, cfg.Checklist)
+	}
+	if !cfg.HasSection(SectionTesting) || cfg.HasSection(SectionMotivation) {
+		t.Errorf("unexpected sections %v", cfg.Sections)
+	}
+	if cfg.EntryDir() != filepath.Join(dir, "docs", "changes") {
+		t.Errorf("unexpected entry dir %q", cfg.EntryDir())
+	}
+	if cfg.Git.Fetch != command.FetchNever || cfg.Git.Remote != "upstream" || !reflect.DeepEqual(cfg.Git.BaseBranches, []string{"trunk", "stable/*"}) {
+		t.Errorf("unexpected git settings %+v", cfg.Git)
+	}
+	if fileList := cfg.FileList(); !fileList.Skip || fileList.CollapseAfter != DefaultCollapseFilesAfter {
+		t.Errorf("expected changed files to be skipped, got %+v", fileList)
+	}
+	if contributors := cfg.ContributorList(); contributors.Skip || contributors.Handles["jane@example.com"] != "@janedoe" {
+		t.Errorf("unexpected contributor settings %+v", contributors)
+	}
+	if len(cfg.Tickets) != 2 || cfg.Tickets[0].Pattern != changelog.TicketTypes[changelog.TicketJira] || len(cfg.TicketPatterns()) != 2 {
+		t.Errorf("unexpected tickets %+v", cfg.Tickets)
+	}
+	wantForges := []Forge{{Host: "git.example.com", Type: "gitlab", URL: "https://gitlab.example.com"}}
+	if !reflect.DeepEqual(cfg.Forges, wantForges) {
+		t.Errorf("expected %v, got %v", wantForges, cfg.Forges)
+	}
+}
+
+func TestLoadFrom_JSON(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, ".changelog.json", `{"sections": [], "output": {"dir": "/var/changes"}}`)
+
+	cfg, err := LoadFrom(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Sections) != 0 {
+		t.Errorf("expected no sections, got %v", cfg.Sections)
+	}
+	if !reflect.DeepEqual(cfg.ChangeTypes, changelog.DefaultChangeTypes) {
+		t.Errorf("expected missing keys to keep defaults, got %v", cfg.ChangeTypes)
+	}
+	if cfg.EntryDir() != "/var/changes" {
+		t.Errorf("expected absolute dir to be kept, got %q", cfg.EntryDir())
+	}
+}
+
+func TestLoadFrom_PrefersYAML(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, ".changelog.yaml", "change_types: [FromYAML]\n")
+	writeConfig(t, dir, ".changelog.json", `{"change_types": ["FromJSON"]}`)
+
+	cfg, err := LoadFrom(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ChangeTypes[0] != "FromYAML" {
+		t.Errorf("expected .changelog.yaml to win, got %v", cfg.ChangeTypes)
+	}
+}
+
+func TestLoadFrom_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"unknown key", "change_type: [Fix]\n", []string{"failed to parse"}},
+		{"empty change types", "change_types: []\n", []string{"change_types must not be empty"}},
+		{"duplicate change type", "change_types: [Fix, fix]\n", []string{`duplicate change type "fix"`}},
+		{"colon in change type", "change_types: ['Fix: bug']\n", []string{"must not contain ':'"}},
+		{"checklist item without id", "checklist: [{text: Deployed}]\n", []string{"checklist items need an id"}},
+		{"custom checklist item without text", "checklist: [{id: deploy}]\n", []string{`checklist item "deploy" needs a text`}},
+		{"duplicate checklist item", "checklist: [{id: tests}, {id: tests}]\n", []string{`duplicate checklist item "tests"`}},
+		{"pre-checked checklist item", "checklist: [{id: tests, checked: true}]\n", []string{"cannot be pre-checked"}},
+		{"bad required_for pattern", "checklist: [{id: tests, required_for: ['release/[']}]\n", []string{"invalid required_for pattern"}},
+		{"unknown section", "sections: [motivation, notes]\n", []string{`unknown section "notes"`}},
+		{"duplicate section", "sections: [testing, testing]\n", []string{`duplicate section "testing"`}},
+		{"unknown front matter", "output: {front_matter: toml}\n", []string{`unknown front_matter "toml"`}},
+		{"unknown fetch policy", "git: {fetch: sometimes}\n", []string{`unknown fetch policy "sometimes"`}},
+		{"negative collapse_after", "files: {collapse_after: -1}\n", []string{"files.collapse_after must not be negative"}},
+		{"bad base branch pattern", "git: {base_branches: ['release/[']}\n", []string{`invalid git.base_branches pattern "release/["`}},
+		{"unknown ticket type", "tickets: [{type: youtrack}]\n", []string{`unknown ticket type "youtrack"`}},
+		{"ticket without pattern", "tickets: [{url: 'https://x/{key}'}]\n", []string{"ticket 1 needs a type or a pattern"}},
+		{"bad ticket pattern", "tickets: [{pattern: 'PAY-(\\d+'}]\n", []string{"invalid ticket pattern"}},
+		{"empty handle", "contributors: {handles: {jane@example.com: '@'}}\n", []string{`contributors.handles: "jane@example.com" needs a handle`}},
+		{"unknown release components", "release: {components: nested}\n", []string{`unknown release.components "nested"`}},
+		{"component without name", "components: [{paths: [api]}]\n", []string{"component 1 needs a name"}},
+		{"duplicate component", "components: [{name: api, paths: [api]}, {name: API, paths: [v2]}]\n", []string{`duplicate component "API"`}},
+		{"component without paths", "components: [{name: api}]\n", []string{`component "api" needs paths`}},
+		{"bad component path", "components: [{name: api, paths: ['api/[']}]\n", []string{`component "api" has invalid path "api/["`}},
+		{"split component without dir", "release: {components: split}\ncomponents: [{name: proto, paths: ['**/*.proto']}]\n", []string{`component "proto" needs a dir for split releases`}},
+		{"split components sharing a dir", "release: {components: split}\ncomponents: [{name: api, paths: [svc/api/**]}, {name: jobs, paths: [svc/jobs], dir: svc/api}]\n", []string{`components "api" and "jobs" share the dir "svc/api"`}},
+		{"unknown testing change type", "change_types: [Fix, Feature]\ncheck: {testing_required_for: [Feature, Hotfix]}\n", []string{`check.testing_required_for: unknown change type "Hotfix"`}},
+		{"forge without host", "forges: [{type: gitlab}]\n", []string{"forges need a host"}},
+		{"unknown forge type", "forges: [{host: git.example.com, type: sourcehut}]\n", []string{`forge "git.example.com": unknown forge type "sourcehut"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeConfig(t, dir, ".changelog.yml", tt.content)
+			_, err := LoadFrom(dir)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected error to contain %q, got %q", want, err.Error())
+				}
+			}
+			if tt.name != "unknown key" && !errors.Is(err, InvalidConfigError) {
+				t.Errorf("expected InvalidConfigError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadFrom_CustomChecklist(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, ".changelog.yaml", `
+checklist:
+  - id: self-review
+    default: true
+  - id: migration
+    text: I ran the migration on staging
+    required_for: [main, release/*]
+`)
+
+	cfg, err := LoadFrom(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Checklist) != 2 {
+		t.Fatalf("expected 2 items, got %v", cfg.Checklist)
+	}
+	item := cfg.Checklist.Item("migration")
+	if item == nil || !item.IsRequiredFor("release/2.0") || item.IsRequiredFor("develop") {
+		t.Errorf("unexpected migration item %+v", item)
+	}
+}
+
+func TestLoadFrom_Components(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, ".changelog.yaml", `
+release:
+  components: split
+components:
+  - name: api
+    paths: [services/api/**, libs/auth]
+  - name: proto
+    paths: ['**/*.proto']
+    dir: proto
+`)
+
+	cfg, err := LoadFrom(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantPaths := []changelog.Component{
+		{Name: "api", Paths: []string{"services/api/**", "libs/auth"}},
+		{Name: "proto", Paths: []string{"**/*.proto"}},
+	}
+	if got := cfg.ComponentPaths(); !reflect.DeepEqual(got, wantPaths) {
+		t.Errorf("expected %v, got %v", wantPaths, got)
+	}
+	if got := cfg.ComponentNames(); !reflect.DeepEqual(got, []string{"api", "proto"}) {
+		t.Errorf("unexpected component names %v", got)
+	}
+	wantFiles := []string{filepath.Join(dir, "services", "api", "CHANGELOG.md"), filepath.Join(dir, "proto", "CHANGELOG.md")}
+	for i, component := range cfg.ReleaseComponents() {
+		if component.File != wantFiles[i] {
+			t.Errorf("expected %s to be released to %s, got %s", component.Name, wantFiles[i], component.File)
+		}
+	}
+
+	defaults, _ := LoadFrom(t.TempDir())
+	if defaults.Release.Components != "grouped" || defaults.ComponentPaths() != nil {
+		t.Errorf("expected grouped releases and no components by default, got %+v", defaults.Release)
+	}
+}
+
+func TestConfig_Check(t *testing.T) {
+	cfg := Default("/repo")
+	if got := cfg.TestingChangeTypes(); !reflect.DeepEqual(got, []string{"New feature"}) {
+		t.Errorf("expected New feature to need testing by default, got %v", got)
+	}
+	cfg.ChangeTypes = []string{"Fix", "Feature"}
+	if got := cfg.TestingChangeTypes(); len(got) != 0 {
+		t.Errorf("expected unconfigured default types to be dropped, got %v", got)
+	}
+	cfg.Check.TestingRequiredFor = []string{"Feature"}
+	if got := cfg.TestingChangeTypes(); !reflect.DeepEqual(got, []string{"Feature"}) {
+		t.Errorf("expected the configured types, got %v", got)
+	}
+
+	if got := cfg.ExcludedPaths(); !reflect.DeepEqual(got, []string{".logs/.changelog"}) {
+		t.Errorf("expected the entry directory to be excluded, got %v", got)
+	}
+	cfg.Output.Dir = "/var/changes"
+	if got := cfg.ExcludedPaths(); got != nil {
+		t.Errorf("expected nothing excluded outside the repository, got %v", got)
+	}
+}
+
+func TestConfig_LoadTemplates(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, "pr.tmpl", "PR: {{.Entry.Title}}")
+	writeConfig(t, dir, ".changelog.yaml", "templates:\n  pr: pr.tmpl\n")
+
+	cfg, err := LoadFrom(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	templates, err := cfg.LoadTemplates()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := templates.Render(changelog.TemplatePR, &changelog.Entry{Title: "Fix"}, nil)
+	if err != nil || got != "PR: Fix" {
+		t.Errorf("expected repository template to be used, got %q, %v", got, err)
+	}
+
+	writeConfig(t, dir, ".changelog.yaml", "templates:\n  html: page.tmpl\n")
+	if _, err := LoadFrom(dir); err == nil || !strings.Contains(err.Error(), `unknown template "html"`) {
+		t.Errorf("expected unknown template error, got %v", err)
+	}
+}
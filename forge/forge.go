@@ -0,0 +1,349 @@
+// Package forge turns git remote URLs into web links for the hosting
+// service behind them: commits, branches, comparisons and new pull
+// requests.
+package forge
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Kind is a hosting service with its own URL layout.
+type Kind string
+
+const (
+	GitHub          Kind = "github"
+	GitLab          Kind = "gitlab"
+	Bitbucket       Kind = "bitbucket"
+	BitbucketServer Kind = "bitbucket-server"
+	AzureDevOps     Kind = "azure-devops"
+	Gitea           Kind = "gitea"
+)
+
+// Kinds lists every supported hosting service.
+var Kinds = []Kind{GitHub, GitLab, Bitbucket, BitbucketServer, AzureDevOps, Gitea}
+
+// Predefined errors
+var (
+	UnsupportedRemoteError = errors.New("unsupported remote url")
+	UnknownKindError       = errors.New("unknown forge type")
+)
+
+// ParseKind reads a hosting service name.
+func ParseKind(name string) (Kind, error) {
+	for _, kind := range Kinds {
+		if strings.EqualFold(name, string(kind)) {
+			return kind, nil
+		}
+	}
+	return "", fmt.Errorf("%w %q, expected one of %s", UnknownKindError, name, kindNames())
+}
+
+// Host describes a self-hosted instance.
+type Host struct {
+	Kind Kind
+	// URL is the web address of the instance, for when it differs from
+	// the remote's host, such as a separate ssh host or a path prefix.
+	URL string
+}
+
+var (
+	hostsMu sync.RWMutex
+	hosts   = map[string]Host{
+		"github.com":        {Kind: GitHub},
+		"gitlab.com":        {Kind: GitLab},
+		"bitbucket.org":     {Kind: Bitbucket},
+		"dev.azure.com":     {Kind: AzureDevOps},
+		"ssh.dev.azure.com": {Kind: AzureDevOps, URL: "https://dev.azure.com"},
+		"codeberg.org":      {Kind: Gitea},
+		"gitea.com":         {Kind: Gitea},
+	}
+)
+
+// Register makes remotes on host, a host name as it appears in remote
+// URLs, use h. It overrides detection for self-hosted instances.
+func Register(host string, h Host) {
+	hostsMu.Lock()
+	defer hostsMu.Unlock()
+	hosts[strings.ToLower(host)] = h
+}
+
+// Hosts returns a copy of every registered host, built-in ones included.
+func Hosts() map[string]Host {
+	hostsMu.RLock()
+	defer hostsMu.RUnlock()
+	registered := make(map[string]Host, len(hosts))
+	for host, h := range hosts {
+		registered[host] = h
+	}
+	return registered
+}
+
+// SetHosts replaces the registered hosts with registered, as returned by
+// Hosts, undoing the Register calls made since.
+func SetHosts(registered map[string]Host) {
+	hostsMu.Lock()
+	defer hostsMu.Unlock()
+	hosts = make(map[string]Host, len(registered))
+	for host, h := range registered {
+		hosts[host] = h
+	}
+}
+
+func lookup(host string) (Host, bool) {
+	hostsMu.RLock()
+	defer hostsMu.RUnlock()
+	h, ok := hosts[strings.ToLower(host)]
+	return h, ok
+}
+
+// Repository is a repository on a hosting service.
+type Repository struct {
+	Kind Kind
+	// BaseURL is the web address of the service, such as
+	// https://gitlab.example.com.
+	BaseURL string
+	// Path is the repository's path below BaseURL without ".git": the
+	// owner and name, nested groups included, for most services;
+	// "<organization>/<project>/<name>" on Azure DevOps and
+	// "<project>/<name>" on Bitbucket Server.
+	Path string
+}
+
+var (
+	scpPattern            = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+	azureVisualStudioHost = regexp.MustCompile(`^([^.]+)\.visualstudio\.com$`)
+)
+
+// Parse reads a remote URL: scp-like (git@host:path), ssh://, git://,
+// http:// or https://. The service is the one registered for the host, or
+// guessed from the host name and path, GitHub's layout being the fallback.
+func Parse(remote string) (Repository, error) {
+	remote = strings.TrimSpace(remote)
+	host, path, scheme, err := splitRemote(remote)
+	if err != nil {
+		return Repository{}, err
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if path == "" || !strings.Contains(path, "/") {
+		return Repository{}, fmt.Errorf("%w %q: no owner and name", UnsupportedRemoteError, remote)
+	}
+
+	hostname := host
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		hostname = host[:i]
+	}
+	registered, known := lookup(host)
+	if !known {
+		registered, known = lookup(hostname)
+	}
+
+	repo := Repository{Kind: registered.Kind, Path: path}
+	if !known {
+		repo.Kind = guessKind(hostname, host, path)
+	}
+	if scheme == "http" || scheme == "https" {
+		repo.BaseURL = scheme + "://" + host
+	} else {
+		// The port of ssh remotes is not the web server's.
+		repo.BaseURL = "https://" + hostname
+	}
+
+	switch repo.Kind {
+	case BitbucketServer:
+		// https remotes live below /scm/, ssh ones at the root.
+		if prefix, rest, ok := strings.Cut(path, "scm/"); ok && (prefix == "" || strings.HasSuffix(prefix, "/")) {
+			repo.BaseURL += "/" + strings.TrimSuffix(prefix, "/")
+			repo.BaseURL = strings.TrimSuffix(repo.BaseURL, "/")
+			repo.Path = rest
+		}
+	case AzureDevOps:
+		repo.Path = azurePath(path)
+		if match := azureVisualStudioHost.FindStringSubmatch(hostname); match != nil {
+			repo.BaseURL = "https://dev.azure.com"
+			repo.Path = match[1] + "/" + repo.Path
+		}
+	}
+	if registered.URL != "" {
+		repo.BaseURL = strings.TrimSuffix(registered.URL, "/")
+	}
+	return repo, nil
+}
+
+func splitRemote(remote string) (host, path, scheme string, err error) {
+	if strings.Contains(remote, "://") {
+		parsed, err := url.Parse(remote)
+		if err != nil {
+			return "", "", "", fmt.Errorf("%w %q: %v", UnsupportedRemoteError, remote, err)
+		}
+		switch parsed.Scheme {
+		case "ssh", "git", "git+ssh", "ssh+git", "http", "https":
+		default:
+			return "", "", "", fmt.Errorf("%w %q: scheme %s", UnsupportedRemoteError, remote, parsed.Scheme)
+		}
+		if parsed.Host == "" {
+			return "", "", "", fmt.Errorf("%w %q: no host", UnsupportedRemoteError, remote)
+		}
+		return parsed.Host, parsed.Path, parsed.Scheme, nil
+	}
+	if match := scpPattern.FindStringSubmatch(remote); match != nil {
+		return match[1], match[2], "ssh", nil
+	}
+	return "", "", "", fmt.Errorf("%w %q", UnsupportedRemoteError, remote)
+}
+
+func guessKind(hostname, host, path string) Kind {
+	lower := strings.ToLower(hostname)
+	switch {
+	case strings.HasSuffix(lower, ".visualstudio.com") || strings.HasSuffix(lower, "dev.azure.com"):
+		return AzureDevOps
+	case strings.Contains(lower, "gitlab"):
+		return GitLab
+	case strings.Contains(lower, "gitea") || strings.Contains(lower, "forgejo"):
+		return Gitea
+	case strings.Contains(lower, "bitbucket"), strings.HasSuffix(host, ":7999"), strings.HasPrefix(path, "scm/"), strings.Contains(path, "/scm/"):
+		return BitbucketServer
+	}
+	return GitHub
+}
+
+// azurePath turns the paths of Azure DevOps remotes,
+// "v3/<org>/<project>/<repo>" over ssh and "<org>/<project>/_git/<repo>"
+// over https, into "<org>/<project>/<repo>".
+func azurePath(path string) string {
+	path = strings.TrimPrefix(path, "v3/")
+	parts := strings.Split(path, "/")
+	var kept []string
+	for _, part := range parts {
+		if part != "_git" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, "/")
+}
+
+// CommitURL links to a commit. With an empty hash it is the prefix commit
+// hashes are appended to.
+func (r Repository) CommitURL(hash string) string {
+	switch r.Kind {
+	case GitLab:
+		return r.web("-/commit/" + hash)
+	case Bitbucket, BitbucketServer:
+		return r.web("commits/" + hash)
+	}
+	return r.web("commit/" + hash)
+}
+
+// BranchURL links to the tip of a branch.
+func (r Repository) BranchURL(branch string) string {
+	switch r.Kind {
+	case GitLab:
+		return r.web("-/tree/" + branch)
+	case Bitbucket:
+		return r.web("branch/" + branch)
+	case BitbucketServer:
+		return r.web("browse?at=" + url.QueryEscape("refs/heads/"+branch))
+	case AzureDevOps:
+		return r.web("?version=GB" + url.QueryEscape(branch))
+	case Gitea:
+		return r.web("src/branch/" + branch)
+	}
+	return r.web("tree/" + branch)
+}
+
+// CompareURL links to the changes on head that are not on base.
+func (r Repository) CompareURL(base, head string) string {
+	switch r.Kind {
+	case GitLab:
+		return r.web("-/compare/" + base + "..." + head)
+	case Bitbucket:
+		return r.web("branches/compare/" + head + "%0D" + base)
+	case BitbucketServer:
+		return r.web("compare/commits?" + refQuery("sourceBranch", head, "targetBranch", base))
+	case AzureDevOps:
+		return r.web("branchCompare?baseVersion=GB" + url.QueryEscape(base) + "&targetVersion=GB" + url.QueryEscape(head))
+	}
+	return r.web("compare/" + base + "..." + head)
+}
+
+// PullRequestURL opens a new pull request, or merge request, from head
+// into base.
+func (r Repository) PullRequestURL(base, head string) string {
+	switch r.Kind {
+	case GitLab:
+		return r.web("-/merge_requests/new?" + url.Values{
+			"merge_request[source_branch]": {head},
+			"merge_request[target_branch]": {base},
+		}.Encode())
+	case Bitbucket:
+		return r.web("pull-requests/new?" + url.Values{"source": {head}, "dest": {base}}.Encode())
+	case BitbucketServer:
+		return r.web("pull-requests?create&" + refQuery("sourceBranch", head, "targetBranch", base))
+	case AzureDevOps:
+		return r.web("pullrequestcreate?" + url.Values{"sourceRef": {head}, "targetRef": {base}}.Encode())
+	case Gitea:
+		return r.web("compare/" + base + "..." + head)
+	}
+	return r.web("compare/" + base + "..." + head + "?expand=1")
+}
+
+// IssueURL links to an issue, or work item on Azure DevOps. It is empty on
+// Bitbucket Server, which leaves issues to Jira.
+func (r Repository) IssueURL(id string) string {
+	switch r.Kind {
+	case GitLab:
+		return r.web("-/issues/" + id)
+	case BitbucketServer:
+		return ""
+	case AzureDevOps:
+		org, rest, _ := strings.Cut(r.Path, "/")
+		project, _, _ := strings.Cut(rest, "/")
+		return r.BaseURL + "/" + org + "/" + project + "/_workitems/edit/" + id
+	}
+	return r.web("issues/" + id)
+}
+
+// web joins the repository's web address with page.
+func (r Repository) web(page string) string {
+	var repo string
+	switch r.Kind {
+	case BitbucketServer:
+		project, name, _ := strings.Cut(r.Path, "/")
+		if user, ok := strings.CutPrefix(project, "~"); ok {
+			repo = "users/" + user + "/repos/" + name
+		} else {
+			repo = "projects/" + strings.ToUpper(project) + "/repos/" + name
+		}
+	case AzureDevOps:
+		parts := strings.SplitN(r.Path, "/", 3)
+		if len(parts) == 3 {
+			repo = parts[0] + "/" + parts[1] + "/_git/" + parts[2]
+		} else {
+			repo = r.Path
+		}
+	default:
+		repo = r.Path
+	}
+
+	base := r.BaseURL + "/" + repo
+	if strings.HasPrefix(page, "?") {
+		return base + page
+	}
+	return base + "/" + page
+}
+
+func refQuery(sourceKey, source, targetKey, target string) string {
+	return sourceKey + "=" + url.QueryEscape("refs/heads/"+source) + "&" + targetKey + "=" + url.QueryEscape("refs/heads/"+target)
+}
+
+func kindNames() string {
+	names := make([]string, len(Kinds))
+	for i, kind := range Kinds {
+		names[i] = string(kind)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,165 @@
+package forge
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		remote string
+		want   Repository
+	}{
+		{"github ssh", "git@github.com:user/repo.git", Repository{GitHub, "https://github.com", "user/repo"}},
+		{"github https", "https://github.com/user/repo.git", Repository{GitHub, "https://github.com", "user/repo"}},
+		{"github https with credentials", "https://token@github.com/user/repo", Repository{GitHub, "https://github.com", "user/repo"}},
+		{"gitlab nested groups", "git@gitlab.com:group/sub/repo.git", Repository{GitLab, "https://gitlab.com", "group/sub/repo"}},
+		{"self-hosted gitlab ssh port", "ssh://git@gitlab.example.com:2222/team/repo.git", Repository{GitLab, "https://gitlab.example.com", "team/repo"}},
+		{"http keeps port", "http://gitea.local:3000/team/repo.git", Repository{Gitea, "http://gitea.local:3000", "team/repo"}},
+		{"bitbucket cloud", "git@bitbucket.org:team/repo.git", Repository{Bitbucket, "https://bitbucket.org", "team/repo"}},
+		{"bitbucket server https", "https://git.example.com/scm/proj/repo.git", Repository{BitbucketServer, "https://git.example.com", "proj/repo"}},
+		{"bitbucket server context path", "https://example.com/git/scm/proj/repo.git", Repository{BitbucketServer, "https://example.com/git", "proj/repo"}},
+		{"bitbucket server ssh", "ssh://git@git.example.com:7999/proj/repo.git", Repository{BitbucketServer, "https://git.example.com", "proj/repo"}},
+		{"azure https", "https://org@dev.azure.com/org/project/_git/repo", Repository{AzureDevOps, "https://dev.azure.com", "org/project/repo"}},
+		{"azure ssh", "git@ssh.dev.azure.com:v3/org/project/repo", Repository{AzureDevOps, "https://dev.azure.com", "org/project/repo"}},
+		{"azure visualstudio", "https://org.visualstudio.com/project/_git/repo", Repository{AzureDevOps, "https://dev.azure.com", "org/project/repo"}},
+		{"codeberg", "git@codeberg.org:user/repo.git", Repository{Gitea, "https://codeberg.org", "user/repo"}},
+		{"unknown host", "git@git.example.com:user/repo.git", Repository{GitHub, "https://git.example.com", "user/repo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.remote)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.remote, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Unsupported(t *testing.T) {
+	for _, remote := range []string{"", "ftp://example.com/repo", "/srv/git/repo.git", "git@github.com-malformed", "https://github.com/repo", "file:///srv/git/repo.git"} {
+		if _, err := Parse(remote); !errors.Is(err, UnsupportedRemoteError) {
+			t.Errorf("Parse(%q): expected UnsupportedRemoteError, got %v", remote, err)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	registered := Hosts()
+	t.Cleanup(func() { SetHosts(registered) })
+	Register("code.example.com", Host{Kind: GitLab})
+	Register("ssh.example.org:7999", Host{Kind: BitbucketServer, URL: "https://bitbucket.example.org/"})
+
+	repo, err := Parse("git@code.example.com:team/repo.git")
+	if err != nil || repo.Kind != GitLab {
+		t.Errorf("expected registered kind, got %+v, %v", repo, err)
+	}
+	repo, err = Parse("ssh://git@ssh.example.org:7999/proj/repo.git")
+	want := Repository{BitbucketServer, "https://bitbucket.example.org", "proj/repo"}
+	if err != nil || repo != want {
+		t.Errorf("expected %+v, got %+v, %v", want, repo, err)
+	}
+
+	SetHosts(registered)
+	if repo, _ := Parse("git@code.example.com:team/repo.git"); repo.Kind == GitLab {
+		t.Error("expected SetHosts to undo the registration")
+	}
+}
+
+func TestRepository_URLs(t *testing.T) {
+	tests := []struct {
+		repo                                        Repository
+		commit, branch, compare, pullRequest, issue string
+	}{
+		{
+			Repository{GitHub, "https://github.com", "user/repo"},
+			"https://github.com/user/repo/commit/abc",
+			"https://github.com/user/repo/tree/feat",
+			"https://github.com/user/repo/compare/main...feat",
+			"https://github.com/user/repo/compare/main...feat?expand=1",
+			"https://github.com/user/repo/issues/12",
+		},
+		{
+			Repository{GitLab, "https://gitlab.com", "group/sub/repo"},
+			"https://gitlab.com/group/sub/repo/-/commit/abc",
+			"https://gitlab.com/group/sub/repo/-/tree/feat",
+			"https://gitlab.com/group/sub/repo/-/compare/main...feat",
+			"https://gitlab.com/group/sub/repo/-/merge_requests/new?merge_request%5Bsource_branch%5D=feat&merge_request%5Btarget_branch%5D=main",
+			"https://gitlab.com/group/sub/repo/-/issues/12",
+		},
+		{
+			Repository{Bitbucket, "https://bitbucket.org", "team/repo"},
+			"https://bitbucket.org/team/repo/commits/abc",
+			"https://bitbucket.org/team/repo/branch/feat",
+			"https://bitbucket.org/team/repo/branches/compare/feat%0Dmain",
+			"https://bitbucket.org/team/repo/pull-requests/new?dest=main&source=feat",
+			"https://bitbucket.org/team/repo/issues/12",
+		},
+		{
+			Repository{BitbucketServer, "https://git.example.com", "proj/repo"},
+			"https://git.example.com/projects/PROJ/repos/repo/commits/abc",
+			"https://git.example.com/projects/PROJ/repos/repo/browse?at=refs%2Fheads%2Ffeat",
+			"https://git.example.com/projects/PROJ/repos/repo/compare/commits?sourceBranch=refs%2Fheads%2Ffeat&targetBranch=refs%2Fheads%2Fmain",
+			"https://git.example.com/projects/PROJ/repos/repo/pull-requests?create&sourceBranch=refs%2Fheads%2Ffeat&targetBranch=refs%2Fheads%2Fmain",
+			"",
+		},
+		{
+			Repository{BitbucketServer, "https://git.example.com", "~jane/repo"},
+			"https://git.example.com/users/jane/repos/repo/commits/abc",
+			"https://git.example.com/users/jane/repos/repo/browse?at=refs%2Fheads%2Ffeat",
+			"https://git.example.com/users/jane/repos/repo/compare/commits?sourceBranch=refs%2Fheads%2Ffeat&targetBranch=refs%2Fheads%2Fmain",
+			"https://git.example.com/users/jane/repos/repo/pull-requests?create&sourceBranch=refs%2Fheads%2Ffeat&targetBranch=refs%2Fheads%2Fmain",
+			"",
+		},
+		{
+			Repository{AzureDevOps, "https://dev.azure.com", "org/project/repo"},
+			"https://dev.azure.com/org/project/_git/repo/commit/abc",
+			"https://dev.azure.com/org/project/_git/repo?version=GBfeat",
+			"https://dev.azure.com/org/project/_git/repo/branchCompare?baseVersion=GBmain&targetVersion=GBfeat",
+			"https://dev.azure.com/org/project/_git/repo/pullrequestcreate?sourceRef=feat&targetRef=main",
+			"https://dev.azure.com/org/project/_workitems/edit/12",
+		},
+		{
+			Repository{Gitea, "https://codeberg.org", "user/repo"},
+			"https://codeberg.org/user/repo/commit/abc",
+			"https://codeberg.org/user/repo/src/branch/feat",
+			"https://codeberg.org/user/repo/compare/main...feat",
+			"https://codeberg.org/user/repo/compare/main...feat",
+			"https://codeberg.org/user/repo/issues/12",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.repo.Kind), func(t *testing.T) {
+			if got := tt.repo.CommitURL("abc"); got != tt.commit {
+				t.Errorf("CommitURL = %q, want %q", got, tt.commit)
+			}
+			if got := tt.repo.BranchURL("feat"); got != tt.branch {
+				t.Errorf("BranchURL = %q, want %q", got, tt.branch)
+			}
+			if got := tt.repo.CompareURL("main", "feat"); got != tt.compare {
+				t.Errorf("CompareURL = %q, want %q", got, tt.compare)
+			}
+			if got := tt.repo.PullRequestURL("main", "feat"); got != tt.pullRequest {
+				t.Errorf("PullRequestURL = %q, want %q", got, tt.pullRequest)
+			}
+			if got := tt.repo.IssueURL("12"); got != tt.issue {
+				t.Errorf("IssueURL = %q, want %q", got, tt.issue)
+			}
+		})
+	}
+}
+
+func TestParseKind(t *testing.T) {
+	if kind, err := ParseKind("GitLab"); err != nil || kind != GitLab {
+		t.Errorf("expected gitlab, got %q, %v", kind, err)
+	}
+	if _, err := ParseKind("sourcehut"); !errors.Is(err, UnknownKindError) {
+		t.Errorf("expected UnknownKindError, got %v", err)
+	}
+}
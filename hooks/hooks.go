@@ -0,0 +1,239 @@
+// Package hooks installs the git hooks that check for a changelog entry,
+// next to whatever the hooks already run.
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PrePush is the hook that checks for an entry before pushing.
+const PrePush = "pre-push"
+
+// The installed lines sit between these markers, so that they can be found
+// again and removed without touching the rest of the hook.
+const (
+	beginMarker = "# >>> changelog-go >>>"
+	endMarker   = "# <<< changelog-go <<<"
+)
+
+// Predefined errors
+var (
+	UnsupportedHookError = errors.New("existing hook is not a shell script")
+	MalformedHookError   = errors.New("changelog-go section of the hook is not closed")
+)
+
+// Status is what Install or Uninstall did to a hook.
+type Status string
+
+const (
+	Installed    Status = "installed"
+	Updated      Status = "updated"
+	Unchanged    Status = "unchanged"
+	Removed      Status = "removed"
+	NotInstalled Status = "not installed"
+)
+
+// shells are the interpreters the hook lines can be added to.
+var shells = map[string]bool{"sh": true, "bash": true, "dash": true, "zsh": true, "ksh": true, "ash": true}
+
+// Script returns the lines the pre-push hook runs: executable checks for
+// an entry for the current branch and blocks the push without one. With
+// wizard it starts the wizard instead when a terminal is available.
+func Script(executable string, wizard bool) string {
+	exe := shellQuote(executable)
+	check := exe + " hooks run " + PrePush
+	var lines []string
+	lines = append(lines,
+		beginMarker,
+		`# Added by "changelog-go hooks install" to require a changelog entry`,
+		`# before pushing. Skip it once with "git push --no-verify".`,
+		"if command -v "+exe+" >/dev/null 2>&1; then",
+	)
+	if wizard {
+		lines = append(lines,
+			"\tif { true </dev/tty; } 2>/dev/null; then",
+			"\t\t"+check+" --wizard </dev/tty || exit 1",
+			"\telse",
+			"\t\t"+check+" </dev/null || exit 1",
+			"\tfi",
+		)
+	} else {
+		lines = append(lines, "\t"+check+" </dev/null || exit 1")
+	}
+	lines = append(lines, "fi", endMarker)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// Install adds script to the hook at path, right after its interpreter
+// line so that it runs before anything that may exit early. A hook without
+// the script is created; one with an older version has it replaced.
+func Install(path, script string) (Status, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return "", fmt.Errorf("failed to create hooks directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(insert("", script)), 0755); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return Installed, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	existing := string(content)
+	rest, installed, err := cut(existing)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	if !isShellScript(rest) {
+		return "", fmt.Errorf("%w: add the changelog-go lines to %s yourself", UnsupportedHookError, path)
+	}
+	updated := insert(rest, script)
+	if updated == existing {
+		return Unchanged, nil
+	}
+	if err := writeKeepingMode(path, updated); err != nil {
+		return "", err
+	}
+	if installed {
+		return Updated, nil
+	}
+	return Installed, nil
+}
+
+// Uninstall removes the script from the hook at path, and the hook itself
+// when nothing else is left in it.
+func Uninstall(path string) (Status, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return NotInstalled, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	rest, installed, err := cut(string(content))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	if !installed {
+		return NotInstalled, nil
+	}
+	if isEmptyScript(rest) {
+		if err := os.Remove(path); err != nil {
+			return "", err
+		}
+		return Removed, nil
+	}
+	if err := writeKeepingMode(path, rest); err != nil {
+		return "", err
+	}
+	return Removed, nil
+}
+
+// IsInstalled reports whether the hook at path runs the script.
+func IsInstalled(path string) (bool, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	_, installed, err := cut(string(content))
+	return installed, err
+}
+
+// cut removes the marked lines from content.
+func cut(content string) (rest string, found bool, err error) {
+	lines := strings.SplitAfter(content, "\n")
+	begin, end := -1, -1
+	for i, line := range lines {
+		switch strings.TrimRight(line, "\r\n") {
+		case beginMarker:
+			if begin < 0 {
+				begin = i
+			}
+		case endMarker:
+			if begin >= 0 && end < 0 {
+				end = i
+			}
+		}
+	}
+	if begin < 0 {
+		return content, false, nil
+	}
+	if end < 0 {
+		return "", false, MalformedHookError
+	}
+	return strings.Join(lines[:begin], "") + strings.Join(lines[end+1:], ""), true, nil
+}
+
+// insert puts script after the interpreter line of content.
+func insert(content, script string) string {
+	if strings.TrimSpace(content) == "" {
+		return "#!/bin/sh\n" + script
+	}
+	if !strings.HasPrefix(content, "#!") {
+		return script + content
+	}
+	shebang, rest, found := strings.Cut(content, "\n")
+	if !found {
+		return shebang + "\n" + script
+	}
+	return shebang + "\n" + script + rest
+}
+
+// isShellScript reports whether content runs with a POSIX-like shell: it
+// names one on its interpreter line, or has none and git runs it with sh.
+func isShellScript(content string) bool {
+	shebang, _, _ := strings.Cut(content, "\n")
+	interpreter, ok := strings.CutPrefix(shebang, "#!")
+	if !ok {
+		return true
+	}
+	fields := strings.Fields(interpreter)
+	if len(fields) == 0 {
+		return false
+	}
+	name := filepath.Base(fields[0])
+	if name == "env" {
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") {
+				return shells[filepath.Base(field)]
+			}
+		}
+		return false
+	}
+	return shells[name]
+}
+
+// isEmptyScript reports whether content is at most an interpreter line.
+func isEmptyScript(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#!") {
+			return false
+		}
+	}
+	return true
+}
+
+func writeKeepingMode(path, content string) error {
+	mode := os.FileMode(0755)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
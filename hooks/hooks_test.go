@@ -0,0 +1,127 @@
+package hooks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readHook(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read hook: %v", err)
+	}
+	return string(content)
+}
+
+func TestInstall_NewHook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hooks", PrePush)
+	script := Script("/usr/local/bin/changelog-go", false)
+
+	status, err := Install(path, script)
+	if err != nil || status != Installed {
+		t.Fatalf("expected %q, got %q, %v", Installed, status, err)
+	}
+	if content := readHook(t, path); content != "#!/bin/sh\n"+script {
+		t.Errorf("unexpected hook:\n%s", content)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm()&0111 == 0 {
+		t.Errorf("expected the hook to be executable, got %v", info.Mode())
+	}
+
+	if status, err := Install(path, script); err != nil || status != Unchanged {
+		t.Errorf("expected a second install to change nothing, got %q, %v", status, err)
+	}
+	if status, err := Install(path, Script("/usr/local/bin/changelog-go", true)); err != nil || status != Updated {
+		t.Errorf("expected the wizard script to replace the old one, got %q, %v", status, err)
+	}
+	if content := readHook(t, path); strings.Count(content, beginMarker) != 1 || !strings.Contains(content, "--wizard </dev/tty") {
+		t.Errorf("unexpected hook:\n%s", content)
+	}
+
+	if status, err := Uninstall(path); err != nil || status != Removed {
+		t.Errorf("expected %q, got %q, %v", Removed, status, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the hook to be deleted, got %v", err)
+	}
+	if status, err := Uninstall(path); err != nil || status != NotInstalled {
+		t.Errorf("expected %q, got %q, %v", NotInstalled, status, err)
+	}
+}
+
+func TestInstall_ExistingHook(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"shell hook", "#!/bin/bash\nnpm test\nexit 0\n", "#!/bin/bash\n<script>npm test\nexit 0\n"},
+		{"env shebang", "#!/usr/bin/env -S bash -e\nmake lint", "#!/usr/bin/env -S bash -e\n<script>make lint"},
+		{"no shebang", "make lint\n", "<script>make lint\n"},
+		{"empty", "", "#!/bin/sh\n<script>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), PrePush)
+			if err := os.WriteFile(path, []byte(tt.content), 0700); err != nil {
+				t.Fatalf("failed to write hook: %v", err)
+			}
+			script := Script("/opt/it's/changelog-go", false)
+
+			if status, err := Install(path, script); err != nil || status != Installed {
+				t.Fatalf("expected %q, got %q, %v", Installed, status, err)
+			}
+			want := strings.Replace(tt.want, "<script>", script, 1)
+			if content := readHook(t, path); content != want {
+				t.Errorf("expected\n%s\ngot\n%s", want, content)
+			}
+			if installed, err := IsInstalled(path); !installed || err != nil {
+				t.Errorf("expected the hook to be installed, got %v, %v", installed, err)
+			}
+
+			if status, err := Uninstall(path); err != nil || status != Removed {
+				t.Fatalf("expected %q, got %q, %v", Removed, status, err)
+			}
+			if tt.content == "" {
+				return
+			}
+			if content := readHook(t, path); content != tt.content {
+				t.Errorf("expected the original hook back, got\n%s", content)
+			}
+			if info, _ := os.Stat(path); info.Mode().Perm() != 0700 {
+				t.Errorf("expected the hook's mode to be kept, got %v", info.Mode())
+			}
+		})
+	}
+}
+
+func TestInstall_Refused(t *testing.T) {
+	dir := t.TempDir()
+
+	python := filepath.Join(dir, "python")
+	os.WriteFile(python, []byte("#!/usr/bin/env python3\nprint('hi')\n"), 0755)
+	if _, err := Install(python, Script("changelog-go", false)); !errors.Is(err, UnsupportedHookError) {
+		t.Errorf("expected UnsupportedHookError, got %v", err)
+	}
+
+	broken := filepath.Join(dir, "broken")
+	os.WriteFile(broken, []byte("#!/bin/sh\n"+beginMarker+"\nchangelog-go hooks run pre-push\n"), 0755)
+	if _, err := Install(broken, Script("changelog-go", false)); !errors.Is(err, MalformedHookError) {
+		t.Errorf("expected MalformedHookError, got %v", err)
+	}
+	if _, err := Uninstall(broken); !errors.Is(err, MalformedHookError) {
+		t.Errorf("expected MalformedHookError, got %v", err)
+	}
+}
+
+func TestScript_Quoting(t *testing.T) {
+	script := Script("/opt/it's/changelog-go", false)
+	if !strings.Contains(script, `'/opt/it'\''s/changelog-go' hooks run pre-push </dev/null || exit 1`) {
+		t.Errorf("expected the executable to be quoted, got\n%s", script)
+	}
+}
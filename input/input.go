@@ -12,6 +12,7 @@ import (
 
 var (
 	TakingInputError = errors.New("error while taking input")
+	NoOptionsError   = errors.New("no options to select from")
 )
 
 type Reader interface {
@@ -20,11 +21,14 @@ type Reader interface {
 	ReadMultiLine(string) (string, error)
 }
 
+// stdin is shared by every StdinReader so input buffered by one read is not
+// lost to the next, which matters when answers are piped in.
+var stdin = bufio.NewReader(os.Stdin)
+
 type StdinReader struct{}
 
 func (sr StdinReader) ReadLine() (string, error) {
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
+	input, err := stdin.ReadString('\n')
 	if err != nil {
 		return "", TakingInputError
 	}
@@ -32,10 +36,10 @@ func (sr StdinReader) ReadLine() (string, error) {
 }
 
 func (sr StdinReader) ReadMultiInstruction(delimiter string) ([]string, error) {
-	reader := bufio.NewReader(os.Stdin)
+	reader := stdin
 	var lines []string
 
-	fmt.Printf("\033[2m(Enter %q on a new line or Ctrl+D to finish input)\033[0m\n", delimiter)
+	fmt.Printf("%s(Enter %q on a new line or Ctrl+D to finish input)%s\n", ui.ColorDim, delimiter, ui.ColorReset)
 
 	for {
 		line, err := reader.ReadString('\n')
@@ -58,10 +62,10 @@ func (sr StdinReader) ReadMultiInstruction(delimiter string) ([]string, error) {
 }
 
 func (sr StdinReader) ReadMultiLine(delimiter string) (string, error) {
-	reader := bufio.NewReader(os.Stdin)
+	reader := stdin
 	var lines []string
 
-	fmt.Printf("\033[2m(Enter %q on a new line or Ctrl+D to finish input)\033[0m\n", delimiter)
+	fmt.Printf("%s(Enter %q on a new line or Ctrl+D to finish input)%s\n", ui.ColorDim, delimiter, ui.ColorReset)
 
 	for {
 		line, err := reader.ReadString('\n')
@@ -93,22 +97,53 @@ type Prompter interface {
 	TakeSingleSelectInput(question string, options []string) (string, error)
 }
 
+// DefaultsPrompter is a Prompter that can pre-fill answers, used when
+// editing an existing entry.
+type DefaultsPrompter interface {
+	Prompter
+	TakeSingleLineInputWithDefault(question, defaultValue string) (string, error)
+	TakeMultiSelectInputWithDefaults(question string, options []string, defaults map[string]string) (map[string]string, error)
+}
+
 type Handler struct {
-	reader   Reader
-	testMode bool
+	reader Reader
+	// lineMode uses numbered, line-based prompts instead of the raw-mode
+	// selectors.
+	lineMode bool
 }
 
+// NewHandler reads from stdin. When stdin or stdout is not a terminal it
+// falls back to line mode and turns colors off.
 func NewHandler() Handler {
-	return Handler{reader: StdinReader{}, testMode: false}
+	lineMode := !ui.Interactive()
+	if lineMode {
+		ui.DisableColors()
+	}
+	return Handler{reader: StdinReader{}, lineMode: lineMode}
 }
 
 func NewTestHandler(reader Reader) Handler {
-	return Handler{reader: reader, testMode: true}
+	return Handler{reader: reader, lineMode: true}
+}
+
+// LineMode reports whether the handler uses line-based prompts.
+func (h Handler) LineMode() bool {
+	return h.lineMode
 }
 
 func (h Handler) TakeSingleLineInput(question string) (string, error) {
+	return h.TakeSingleLineInputWithDefault(question, "")
+}
+
+// TakeSingleLineInputWithDefault returns defaultValue when the answer is
+// left empty. Without a default an empty answer is asked again.
+func (h Handler) TakeSingleLineInputWithDefault(question, defaultValue string) (string, error) {
+	hint := ""
+	if defaultValue != "" {
+		hint = fmt.Sprintf(" %s[%s]%s", ui.ColorDim, defaultValue, ui.ColorReset)
+	}
 	for {
-		fmt.Printf("\033[34m? \033[1m%s:\033[0m ", question)
+		fmt.Printf("%s? %s%s:%s%s ", ui.ColorBlue, ui.ColorBold, question, ui.ColorReset, hint)
 		input, err := h.reader.ReadLine()
 		if err != nil {
 			return "", err
@@ -117,25 +152,28 @@ func (h Handler) TakeSingleLineInput(question string) (string, error) {
 		if strings.TrimSpace(input) != "" {
 			return input, nil
 		}
+		if defaultValue != "" {
+			return defaultValue, nil
+		}
 
-		fmt.Printf("\033[31m⚠ Input cannot be empty. Please try again.\033[0m\n")
+		fmt.Printf("%s⚠ Input cannot be empty. Please try again.%s\n", ui.ColorRed, ui.ColorReset)
 	}
 }
 
 func (h Handler) TakeMultiLineInput(question string) (string, error) {
-	fmt.Printf("\033[34m? \033[1m%s:\033[0m ", question)
+	fmt.Printf("%s? %s%s:%s ", ui.ColorBlue, ui.ColorBold, question, ui.ColorReset)
 	input, error := h.reader.ReadMultiLine("EOF")
 	return input, error
 }
 
 func (h Handler) TakeMultiInstructionInput(question string) ([]string, error) {
-	fmt.Printf("\033[34m? \033[1m%s:\033[0m ", question)
+	fmt.Printf("%s? %s%s:%s ", ui.ColorBlue, ui.ColorBold, question, ui.ColorReset)
 	input, error := h.reader.ReadMultiInstruction("EOF")
 	return input, error
 }
 
 func (h Handler) TakeBooleanTypeInput(question string, defaultValue bool) (bool, error) {
-	if h.testMode {
+	if h.lineMode {
 		return h.takeBooleanInputFallback(question, defaultValue)
 	}
 	boolSelect := ui.NewBooleanSelect(question, defaultValue)
@@ -172,68 +210,26 @@ func (h Handler) takeBooleanInputFallback(question string, defaultValue bool) (b
 }
 
 func (h Handler) TakeMultiSelectInput(question string, options []string) (map[string]string, error) {
-	if h.testMode {
-		// Fallback to old behavior for testing
-		for {
-			fmt.Printf("%s:\n", question)
-			for i, option := range options {
-				fmt.Printf("%d. %s\n", i+1, option)
-			}
-			fmt.Print("Select options (comma-separated numbers): ")
-
-			input, err := h.reader.ReadLine()
-			if err != nil {
-				return nil, err
-			}
-
-			result := make(map[string]string)
-			for _, option := range options {
-				result[option] = ""
-			}
-
-			if strings.TrimSpace(input) == "" {
-				fmt.Println("Please select at least one option.")
-				continue
-			}
-
-			hasSelection := false
-			selections := strings.Split(input, ",")
-			for _, sel := range selections {
-				sel = strings.TrimSpace(sel)
-				if sel == "" {
-					continue
-				}
-
-				var idx int
-				if _, err := fmt.Sscanf(sel, "%d", &idx); err != nil {
-					continue
-				}
-
-				if idx >= 1 && idx <= len(options) {
-					option := options[idx-1]
-					hasSelection = true
-					if strings.ToLower(option) == "other" {
-						customInput, err := h.TakeSingleLineInput("Please specify")
-						if err != nil {
-							return nil, err
-						}
-						result[option] = customInput
-					} else {
-						result[option] = option
-					}
-				}
-			}
+	return h.TakeMultiSelectInputWithDefaults(question, options, nil)
+}
 
-			if hasSelection {
-				return result, nil
+// TakeMultiSelectInputWithDefaults starts with the options selected in
+// defaults, a map shaped like the result. A custom "Other" text in defaults
+// is offered again when "Other" stays selected.
+func (h Handler) TakeMultiSelectInputWithDefaults(question string, options []string, defaults map[string]string) (map[string]string, error) {
+	var result map[string]string
+	var err error
+	if h.lineMode {
+		result, err = h.takeMultiSelectInputFallback(question, options, defaults)
+	} else {
+		var selected []string
+		for _, option := range options {
+			if defaults[option] != "" {
+				selected = append(selected, option)
 			}
-
-			fmt.Println("Please select at least one valid option.")
 		}
+		result, err = ui.NewMultiSelect(options).Preselect(selected).Run(question)
 	}
-
-	multiSelect := ui.NewMultiSelect(options)
-	result, err := multiSelect.Run(question)
 	if err != nil {
 		return nil, err
 	}
@@ -241,7 +237,11 @@ func (h Handler) TakeMultiSelectInput(question string, options []string) (map[st
 	// Handle "Other" option with custom input
 	for option, value := range result {
 		if value != "" && strings.ToLower(option) == "other" {
-			customInput, err := h.TakeSingleLineInput("Please specify")
+			defaultValue := defaults[option]
+			if defaultValue == option {
+				defaultValue = ""
+			}
+			customInput, err := h.TakeSingleLineInputWithDefault("Please specify", defaultValue)
 			if err != nil {
 				return nil, err
 			}
@@ -252,21 +252,93 @@ func (h Handler) TakeMultiSelectInput(question string, options []string) (map[st
 	return result, nil
 }
 
+func (h Handler) takeMultiSelectInputFallback(question string, options []string, defaults map[string]string) (map[string]string, error) {
+	var current []string
+	for i, option := range options {
+		if defaults[option] != "" {
+			current = append(current, fmt.Sprint(i+1))
+		}
+	}
+
+	for {
+		fmt.Printf("%s:\n", question)
+		for i, option := range options {
+			fmt.Printf("%d. %s\n", i+1, option)
+		}
+		if len(current) > 0 {
+			fmt.Printf("Select options (comma-separated numbers) [%s]: ", strings.Join(current, ","))
+		} else {
+			fmt.Print("Select options (comma-separated numbers): ")
+		}
+
+		input, err := h.reader.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.TrimSpace(input) == "" {
+			if len(current) == 0 {
+				fmt.Println("Please select at least one option.")
+				continue
+			}
+			input = strings.Join(current, ",")
+		}
+
+		result := make(map[string]string)
+		for _, option := range options {
+			result[option] = ""
+		}
+
+		hasSelection := false
+		selections := strings.Split(input, ",")
+		for _, sel := range selections {
+			sel = strings.TrimSpace(sel)
+			if sel == "" {
+				continue
+			}
+
+			var idx int
+			if _, err := fmt.Sscanf(sel, "%d", &idx); err != nil {
+				continue
+			}
+
+			if idx >= 1 && idx <= len(options) {
+				option := options[idx-1]
+				hasSelection = true
+				result[option] = option
+			}
+		}
+
+		if hasSelection {
+			return result, nil
+		}
+
+		fmt.Println("Please select at least one valid option.")
+	}
+}
+
 func (h Handler) TakeSingleSelectInput(question string, options []string) (string, error) {
-	if h.testMode {
-		// Fallback to old behavior for testing
+	if len(options) == 0 {
+		return "", fmt.Errorf("%w: %s", NoOptionsError, question)
+	}
+	if h.lineMode {
 		for {
 			fmt.Printf("%s:\n", question)
 			for i, option := range options {
 				fmt.Printf("%d. %s\n", i+1, option)
 			}
-			fmt.Print("Select option (number): ")
+			fmt.Printf("Select option (number) %s[1]%s: ", ui.ColorDim, ui.ColorReset)
 
 			input, err := h.reader.ReadLine()
 			if err != nil {
 				return "", err
 			}
 
+			// An empty answer picks the first option, like the selector.
+			if strings.TrimSpace(input) == "" {
+				return options[0], nil
+			}
+
 			var idx int
 			if _, err := fmt.Sscanf(strings.TrimSpace(input), "%d", &idx); err != nil {
 				fmt.Println("Please enter a valid number.")
@@ -1,9 +1,12 @@
 package input
 
 import (
+	"bufio"
 	"errors"
 	"strings"
 	"testing"
+
+	"github.com/abirhasanmubin/changelog-go/ui"
 )
 
 type MockReader struct {
@@ -251,6 +254,44 @@ func TestTakeMultiSelectInput(t *testing.T) {
 	}
 }
 
+func TestTakeSingleSelectInput(t *testing.T) {
+	options := []string{"Go", "Python", "JavaScript"}
+
+	tests := []struct {
+		name      string
+		responses []string
+		expected  string
+		wantError bool
+	}{
+		{"selection", []string{"2"}, "Python", false},
+		{"empty picks the first option", []string{""}, "Go", false},
+		{"invalid then valid", []string{"x", "4", "3"}, "JavaScript", false},
+		{"error", []string{"ERROR"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewTestHandler(&MockReader{responses: tt.responses})
+			result, err := handler.TakeSingleSelectInput("test question", options)
+
+			if tt.wantError && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+
+	handler := NewTestHandler(&MockReader{responses: []string{""}})
+	if _, err := handler.TakeSingleSelectInput("test question", nil); !errors.Is(err, NoOptionsError) {
+		t.Errorf("expected NoOptionsError without options, got %v", err)
+	}
+}
+
 func TestNewHandler(t *testing.T) {
 	handler := NewHandler()
 	if handler.reader == nil {
@@ -261,13 +302,102 @@ func TestNewHandler(t *testing.T) {
 	}
 }
 
+func TestNewHandler_LineModeWithoutTerminal(t *testing.T) {
+	if ui.Interactive() {
+		t.Skip("stdin and stdout are terminals")
+	}
+	colors := ui.ColorReset
+	defer func() { ui.ColorReset = colors }()
+
+	handler := NewHandler()
+	if !handler.LineMode() {
+		t.Error("expected line mode without a terminal")
+	}
+	if ui.ColorsEnabled() {
+		t.Error("expected colors to be disabled without a terminal")
+	}
+}
+
+func TestStdinReader_SharedBuffer(t *testing.T) {
+	saved := stdin
+	defer func() { stdin = saved }()
+	stdin = bufio.NewReader(strings.NewReader("first\nsecond\nline one\nline two\nEOF\n"))
+
+	reader := StdinReader{}
+	for _, want := range []string{"first", "second"} {
+		got, err := reader.ReadLine()
+		if err != nil || got != want {
+			t.Errorf("expected %q, got %q (%v)", want, got, err)
+		}
+	}
+	if got, _ := reader.ReadMultiLine("EOF"); got != "line one\nline two" {
+		t.Errorf("expected piped lines to survive between reads, got %q", got)
+	}
+}
+
 func TestNewTestHandler(t *testing.T) {
 	mockReader := &MockReader{}
 	handler := NewTestHandler(mockReader)
 	if handler.reader != mockReader {
 		t.Error("expected reader to be the provided mock reader")
 	}
-	if !handler.testMode {
-		t.Error("expected test mode to be true")
+	if !handler.LineMode() {
+		t.Error("expected line mode to be true")
+	}
+}
+
+func TestTakeSingleLineInputWithDefault(t *testing.T) {
+	tests := []struct {
+		name         string
+		responses    []string
+		defaultValue string
+		expected     string
+	}{
+		{"empty keeps default", []string{""}, "old title", "old title"},
+		{"answer replaces default", []string{"new title"}, "old title", "new title"},
+		{"no default asks again", []string{"", "value"}, "", "value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewTestHandler(&MockReader{responses: tt.responses})
+			result, err := handler.TakeSingleLineInputWithDefault("title", tt.defaultValue)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestTakeMultiSelectInputWithDefaults(t *testing.T) {
+	options := []string{"Bug fix", "New feature", "Other"}
+	defaults := map[string]string{"Bug fix": "Bug fix", "New feature": "", "Other": "Security"}
+
+	tests := []struct {
+		name      string
+		responses []string
+		expected  map[string]string
+	}{
+		{"empty keeps defaults", []string{"", ""}, map[string]string{"Bug fix": "Bug fix", "New feature": "", "Other": "Security"}},
+		{"new selection", []string{"2"}, map[string]string{"Bug fix": "", "New feature": "New feature", "Other": ""}},
+		{"other text can change", []string{"3", "Performance"}, map[string]string{"Bug fix": "", "New feature": "", "Other": "Performance"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewTestHandler(&MockReader{responses: tt.responses})
+			result, err := handler.TakeMultiSelectInputWithDefaults("types", options, defaults)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for option, want := range tt.expected {
+				if result[option] != want {
+					t.Errorf("expected %s=%q, got %q", option, want, result[option])
+				}
+			}
+		})
 	}
 }
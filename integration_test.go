@@ -23,9 +23,9 @@ func TestFullWorkflow_Integration(t *testing.T) {
 	entry := changelog.NewEntry()
 	entry.Title = "Integration Test Title"
 	entry.Description = "Test description\nSecond line"
-	entry.Checklist.SelfReview = true
-	entry.Checklist.Documentation = true
-	entry.Checklist.ReadmeUpdated = true
+	entry.Checklist.Set(changelog.ChecklistSelfReview, true)
+	entry.Checklist.Set(changelog.ChecklistDocumentation, true)
+	entry.Checklist.Set(changelog.ChecklistReadmeUpdated, true)
 
 	// Create selected types for testing
 	selectedTypes := map[string]string{
@@ -48,6 +48,16 @@ func TestFullWorkflow_Integration(t *testing.T) {
 	if !strings.Contains(markdown, "- [x] New feature") {
 		t.Error("Generated markdown should show selected new feature")
 	}
+	for _, item := range changelog.DefaultChecklist() {
+		checked := item.ID == changelog.ChecklistSelfReview || item.ID == changelog.ChecklistDocumentation || item.ID == changelog.ChecklistReadmeUpdated
+		line := "- [ ] " + item.Text
+		if checked {
+			line = "- [x] " + item.Text
+		}
+		if !strings.Contains(markdown, line) {
+			t.Errorf("Generated markdown should contain %q", line)
+		}
+	}
 
 	// Test file saving
 	filePath := filepath.Join(tempDir, "test-changelog")
@@ -121,13 +131,13 @@ func TestEndToEndFileGeneration(t *testing.T) {
 		Todos:        []string{"Review code", "Update docs"},
 		ModelChanges: []string{"Added new field", "Updated validation"},
 		Testing:      []string{"Unit tests", "Integration tests"},
-		Checklist: changelog.Checklist{
-			SelfReview:       true,
-			IncludesTesting:  true,
-			Documentation:    true,
-			EngineerReachout: false,
-			ReadmeUpdated:    true,
-		},
+		Checklist: func() changelog.Checklist {
+			checklist := changelog.DefaultChecklist()
+			for _, id := range []string{changelog.ChecklistSelfReview, changelog.ChecklistIncludesTesting, changelog.ChecklistDocumentation, changelog.ChecklistReadmeUpdated} {
+				checklist.Set(id, true)
+			}
+			return checklist
+		}(),
 		Metadata: changelog.Metadata{
 			Branch:   "test-branch",
 			UserName: "test-user",
@@ -1,9 +1,16 @@
 package main
 
 import (
-	"github.com/abirhasanmubin/changelog-go/prompt"
+	"os"
+
+	"github.com/abirhasanmubin/changelog-go/cli"
 )
 
+// version is overridden at build time with -ldflags "-X main.version=...".
+var version = "dev"
+
 func main() {
-	prompt.Generate()
+	app := cli.New()
+	app.Version = version
+	os.Exit(app.Run(os.Args[1:]))
 }
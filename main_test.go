@@ -7,7 +7,7 @@ import (
 
 func TestMain(t *testing.T) {
 	// Test that main function exists and can be called
-	// Since main() calls prompt.Generate() which requires user input,
+	// Since main() dispatches to commands that require user input,
 	// we can't easily test it without mocking, but we can test that it compiles
 	// The fact that this test runs means the main function compiled successfully
 	t.Log("Main function compiled successfully")
@@ -0,0 +1,283 @@
+package prompt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/abirhasanmubin/changelog-go/changelog"
+	"github.com/abirhasanmubin/changelog-go/command"
+	"github.com/abirhasanmubin/changelog-go/config"
+)
+
+var (
+	InvalidAnswersError = errors.New("invalid answers")
+)
+
+// Answers holds every response the wizard asks for, so an entry can be
+// produced without a terminal. Types are change type names; a custom
+// "Other" type is written as "Other: <text>". Checklist lists the ids of
+// the checked items, a nil Checklist keeps the wizard's defaults. Likewise
+// nil Components keep the components suggested from the changed files.
+type Answers struct {
+	Title        string   `json:"title" yaml:"title"`
+	Types        []string `json:"types" yaml:"types"`
+	Motivation   string   `json:"motivation" yaml:"motivation"`
+	Description  string   `json:"description" yaml:"description"`
+	Todos        []string `json:"todos" yaml:"todos"`
+	ModelChanges []string `json:"model_changes" yaml:"model_changes"`
+	Testing      []string `json:"testing" yaml:"testing"`
+	Checklist    []string `json:"checklist" yaml:"checklist"`
+	Components   []string `json:"components" yaml:"components"`
+	TargetBranch string   `json:"target" yaml:"target"`
+	Output       string   `json:"output" yaml:"output"`
+}
+
+var outputFormats = map[string]string{
+	"file": "Generate file",
+	"copy": "Copy Bitbucket PR text",
+	"show": "Show Bitbucket PR text",
+}
+
+// LoadAnswers reads answers from a JSON or YAML file, chosen by extension.
+// Unknown keys are rejected so typos don't silently drop answers.
+func LoadAnswers(path string) (Answers, error) {
+	var answers Answers
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return answers, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&answers)
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&answers)
+	default:
+		return answers, fmt.Errorf("unsupported answers file %q, expected .json, .yaml or .yml", path)
+	}
+	if err != nil {
+		return answers, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return answers, nil
+}
+
+// Validate reports every answer the wizard would have refused with cfg,
+// instead of re-prompting for it.
+func (a Answers) Validate(cfg config.Config) error {
+	var problems []string
+
+	if strings.TrimSpace(a.Title) == "" {
+		problems = append(problems, "title is required")
+	}
+
+	if len(a.Types) == 0 {
+		problems = append(problems, "at least one change type is required")
+	}
+	for _, value := range a.Types {
+		option, custom, ok := matchChangeType(cfg.ChangeTypes, value)
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("unknown change type %q", value))
+		case isOther(option) && strings.TrimSpace(custom) == "":
+			problems = append(problems, fmt.Sprintf("change type %q needs a description, e.g. \"%s: <text>\"", option, option))
+		case !isOther(option) && strings.TrimSpace(custom) != "":
+			problems = append(problems, fmt.Sprintf("change type %q does not take a description", option))
+		}
+	}
+
+	for _, id := range a.Checklist {
+		if !knownChecklistID(cfg.Checklist, id) {
+			problems = append(problems, fmt.Sprintf("unknown checklist item %q", id))
+		}
+	}
+	for _, item := range cfg.Checklist {
+		if item.IsRequiredFor(a.TargetBranch) && !a.checked(item) {
+			problems = append(problems, fmt.Sprintf("checklist item %q is required for target %q", item.ID, a.TargetBranch))
+		}
+	}
+
+	for _, name := range a.Components {
+		if _, ok := matchComponent(cfg.ComponentNames(), name); !ok {
+			problems = append(problems, fmt.Sprintf("unknown component %q", name))
+		}
+	}
+
+	sections := []struct {
+		name     string
+		answered bool
+	}{
+		{config.SectionMotivation, a.Motivation != ""},
+		{config.SectionDescription, a.Description != ""},
+		{config.SectionTodos, len(a.Todos) > 0},
+		{config.SectionModelChanges, len(a.ModelChanges) > 0},
+		{config.SectionTesting, len(a.Testing) > 0},
+	}
+	for _, section := range sections {
+		if section.answered && !cfg.HasSection(section.name) {
+			problems = append(problems, fmt.Sprintf("section %q is not enabled in the config", section.name))
+		}
+	}
+
+	if a.Output != "" {
+		if _, ok := resolveOutputFormat(a.Output); !ok {
+			problems = append(problems, fmt.Sprintf("unknown output %q, expected file, copy or show", a.Output))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", InvalidAnswersError, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// GenerateFromAnswers produces the same entry and output as
+// GenerateWithConfig without prompting.
+func GenerateFromAnswers(cfg config.Config, answers Answers) error {
+	setupColors()
+	if answers.TargetBranch == "" {
+		answers.TargetBranch = detectTargetBranch(command.New())
+	}
+	if err := answers.Validate(cfg); err != nil {
+		return err
+	}
+
+	entry, err := newEntry(cfg)
+	if err != nil {
+		return err
+	}
+	selectedTypes := answers.apply(cfg, &entry)
+	entry.PopulateCommitHistory(answers.TargetBranch)
+	if answers.Components == nil {
+		entry.Components = entry.SuggestedComponents()
+	}
+	warnStaleRemote()
+	warnChangeTypeConflicts(&entry, selectedTypes)
+	warnMissingChecklist(&entry)
+
+	outputFormat, _ := resolveOutputFormat(answers.Output)
+	return handleOutput(&entry, selectedTypes, outputFormat, cfg.EntryDir())
+}
+
+// apply fills entry the way the interactive prompts would and returns the
+// selected change types.
+func (a Answers) apply(cfg config.Config, entry *changelog.Entry) map[string]string {
+	selectedTypes := make(map[string]string)
+	for _, option := range cfg.ChangeTypes {
+		selectedTypes[option] = ""
+	}
+	for _, value := range a.Types {
+		option, custom, _ := matchChangeType(cfg.ChangeTypes, value)
+		if isOther(option) {
+			selectedTypes[option] = strings.TrimSpace(custom)
+		} else {
+			selectedTypes[option] = option
+		}
+	}
+
+	entry.Title = strings.TrimSpace(a.Title)
+	entry.Motivation = a.Motivation
+	entry.Description = a.Description
+	entry.Todos = nonEmpty(a.Todos)
+	entry.ModelChanges = nonEmpty(a.ModelChanges)
+	entry.Testing = nonEmpty(a.Testing)
+
+	entry.Components = nil
+	for _, name := range a.Components {
+		if component, ok := matchComponent(cfg.ComponentNames(), name); ok && !contains(entry.Components, component) {
+			entry.Components = append(entry.Components, component)
+		}
+	}
+
+	if entry.Checklist == nil {
+		entry.Checklist = cfg.Checklist.Clone()
+	}
+	for i, item := range entry.Checklist {
+		entry.Checklist[i].Checked = a.checked(item)
+	}
+
+	return selectedTypes
+}
+
+// checked reports the answer for item, its default when the checklist was
+// not answered at all.
+func (a Answers) checked(item changelog.ChecklistItem) bool {
+	if a.Checklist == nil {
+		return item.Default
+	}
+	return containsFold(a.Checklist, item.ID)
+}
+
+// matchChangeType finds the change type option for value, splitting off the
+// custom text of "Other: <text>".
+func matchChangeType(changeTypes []string, value string) (option string, custom string, ok bool) {
+	name, custom, _ := strings.Cut(value, ":")
+	name = strings.TrimSpace(name)
+	for _, option := range changeTypes {
+		if strings.EqualFold(option, name) {
+			return option, custom, true
+		}
+	}
+	return "", "", false
+}
+
+// matchComponent finds the configured component named name, ignoring case.
+func matchComponent(components []string, name string) (string, bool) {
+	for _, component := range components {
+		if strings.EqualFold(component, strings.TrimSpace(name)) {
+			return component, true
+		}
+	}
+	return "", false
+}
+
+func isOther(option string) bool {
+	return strings.ToLower(option) == "other"
+}
+
+func knownChecklistID(items changelog.Checklist, id string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item.ID, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func resolveOutputFormat(output string) (string, bool) {
+	if output == "" {
+		return outputFormats["file"], true
+	}
+	for key, format := range outputFormats {
+		if strings.EqualFold(output, key) || strings.EqualFold(output, format) {
+			return format, true
+		}
+	}
+	return "", false
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(strings.TrimSpace(value), target) {
+			return true
+		}
+	}
+	return false
+}
+
+func nonEmpty(items []string) []string {
+	if len(items) == 0 {
+		return nil
+	}
+	return items
+}
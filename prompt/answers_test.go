@@ -0,0 +1,319 @@
+package prompt
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/abirhasanmubin/changelog-go/changelog"
+	"github.com/abirhasanmubin/changelog-go/config"
+)
+
+// scriptedPrompter replays queued answers per prompt kind, in call order.
+type scriptedPrompter struct {
+	lines        []string
+	multiLines   []string
+	instructions [][]string
+	booleans     []bool
+	multiSelect  map[string]string
+}
+
+func (s *scriptedPrompter) TakeSingleLineInput(question string) (string, error) {
+	line := s.lines[0]
+	s.lines = s.lines[1:]
+	return line, nil
+}
+
+func (s *scriptedPrompter) TakeMultiLineInput(question string) (string, error) {
+	text := s.multiLines[0]
+	s.multiLines = s.multiLines[1:]
+	return text, nil
+}
+
+func (s *scriptedPrompter) TakeMultiInstructionInput(question string) ([]string, error) {
+	items := s.instructions[0]
+	s.instructions = s.instructions[1:]
+	return items, nil
+}
+
+func (s *scriptedPrompter) TakeBooleanTypeInput(question string, defaultValue bool) (bool, error) {
+	value := s.booleans[0]
+	s.booleans = s.booleans[1:]
+	return value, nil
+}
+
+func (s *scriptedPrompter) TakeMultiSelectInput(question string, options []string) (map[string]string, error) {
+	return s.multiSelect, nil
+}
+
+func (s *scriptedPrompter) TakeSingleSelectInput(question string, options []string) (string, error) {
+	return options[0], nil
+}
+
+func TestAnswers_MatchesInteractiveFlow(t *testing.T) {
+	prompter := &scriptedPrompter{
+		lines:        []string{"Fix login"},
+		multiLines:   []string{"Tokens expired early", "Refresh tokens before expiry"},
+		instructions: [][]string{{"Run migration"}, {"Open login page", "Wait an hour"}},
+		// motivation, instructions, model changes, testing, then the checklist
+		booleans: []bool{true, true, false, true, true, true, false, false, false},
+		multiSelect: map[string]string{
+			"Bug fix": "Bug fix", "New feature": "", "Code refactor": "",
+			"Breaking change": "", "Documentation update": "", "Other": "Security",
+		},
+	}
+
+	cfg := config.Default("")
+	interactive := changelog.Entry{}
+	interactiveTypes := promptChangeTypes(prompter, cfg.ChangeTypes, nil)
+	promptBasicInfo(&interactive, prompter)
+	promptOptionalSections(&interactive, prompter, cfg.Sections)
+	promptChecklist(&interactive, prompter)
+
+	answers := Answers{
+		Title:       "Fix login",
+		Types:       []string{"Bug fix", "Other: Security"},
+		Motivation:  "Tokens expired early",
+		Description: "Refresh tokens before expiry",
+		Todos:       []string{"Run migration"},
+		Testing:     []string{"Open login page", "Wait an hour"},
+		Checklist:   []string{"self-review", "tests"},
+	}
+	if err := answers.Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fromAnswers := changelog.Entry{}
+	answerTypes := answers.apply(cfg, &fromAnswers)
+
+	if !reflect.DeepEqual(interactive, fromAnswers) {
+		t.Errorf("entries differ:\ninteractive: %+v\nanswers:     %+v", interactive, fromAnswers)
+	}
+	if !reflect.DeepEqual(interactiveTypes, answerTypes) {
+		t.Errorf("selected types differ:\ninteractive: %v\nanswers:     %v", interactiveTypes, answerTypes)
+	}
+	if interactive.GenerateMarkdown(interactiveTypes) != fromAnswers.GenerateMarkdown(answerTypes) {
+		t.Error("expected identical markdown output")
+	}
+}
+
+func TestAnswers_Apply_ChecklistDefaults(t *testing.T) {
+	cfg := config.Default("")
+	entry := changelog.Entry{}
+	Answers{Title: "x", Types: []string{"Bug fix"}}.apply(cfg, &entry)
+	if !entry.Checklist.Checked(changelog.ChecklistSelfReview) {
+		t.Error("expected SelfReview to default to true")
+	}
+	if entry.Checklist.Checked(changelog.ChecklistIncludesTesting) {
+		t.Error("expected IncludesTesting to default to false")
+	}
+
+	entry = changelog.Entry{}
+	Answers{Title: "x", Types: []string{"Bug fix"}, Checklist: []string{}}.apply(cfg, &entry)
+	if entry.Checklist.Checked(changelog.ChecklistSelfReview) {
+		t.Error("expected an empty checklist to uncheck every item")
+	}
+}
+
+func TestAnswers_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers Answers
+		want    []string
+	}{
+		{"valid", Answers{Title: "x", Types: []string{"new feature"}, Output: "copy"}, nil},
+		{"missing title", Answers{Types: []string{"Bug fix"}}, []string{"title is required"}},
+		{"missing types", Answers{Title: "x"}, []string{"at least one change type is required"}},
+		{"unknown type", Answers{Title: "x", Types: []string{"Hotfix"}}, []string{`unknown change type "Hotfix"`}},
+		{"other without text", Answers{Title: "x", Types: []string{"Other"}}, []string{`"Other" needs a description`}},
+		{"description on plain type", Answers{Title: "x", Types: []string{"Bug fix: oops"}}, []string{`"Bug fix" does not take a description`}},
+		{"unknown checklist item", Answers{Title: "x", Types: []string{"Bug fix"}, Checklist: []string{"deploy"}}, []string{`unknown checklist item "deploy"`}},
+		{"unknown output", Answers{Title: "x", Types: []string{"Bug fix"}, Output: "print"}, []string{`unknown output "print"`}},
+		{"reports every problem", Answers{Types: []string{"Hotfix"}}, []string{"title is required", `unknown change type "Hotfix"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.answers.Validate(config.Default(""))
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, InvalidAnswersError) {
+				t.Fatalf("expected InvalidAnswersError, got %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected error to contain %q, got %q", want, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestAnswers_Validate_RequiredChecklist(t *testing.T) {
+	cfg := config.Default("")
+	cfg.Checklist = changelog.Checklist{
+		{ID: "migration", Text: "I ran the migration on staging", RequiredFor: []string{"main", "release/*"}},
+	}
+
+	tests := []struct {
+		name    string
+		answers Answers
+		wantErr bool
+	}{
+		{"required and unchecked", Answers{Title: "x", Types: []string{"Bug fix"}, TargetBranch: "release/1.2", Checklist: []string{}}, true},
+		{"required and checked", Answers{Title: "x", Types: []string{"Bug fix"}, TargetBranch: "main", Checklist: []string{"migration"}}, false},
+		{"not required for target", Answers{Title: "x", Types: []string{"Bug fix"}, TargetBranch: "develop"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.answers.Validate(cfg)
+			if tt.wantErr && (err == nil || !strings.Contains(err.Error(), `checklist item "migration" is required`)) {
+				t.Errorf("expected required checklist error, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadAnswers(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		return path
+	}
+
+	t.Run("yaml", func(t *testing.T) {
+		path := write("answers.yaml", "title: Fix login\ntypes:\n  - Bug fix\ntesting:\n  - Log in\nchecklist: [self-review]\ntarget: main\n")
+		answers, err := LoadAnswers(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := Answers{
+			Title:        "Fix login",
+			Types:        []string{"Bug fix"},
+			Testing:      []string{"Log in"},
+			Checklist:    []string{"self-review"},
+			TargetBranch: "main",
+		}
+		if !reflect.DeepEqual(answers, want) {
+			t.Errorf("expected %+v, got %+v", want, answers)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		path := write("answers.json", `{"title": "Fix login", "types": ["Bug fix"], "model_changes": ["User.email"]}`)
+		answers, err := LoadAnswers(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if answers.Title != "Fix login" || len(answers.ModelChanges) != 1 {
+			t.Errorf("unexpected answers %+v", answers)
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		path := write("typo.yaml", "titel: Fix login\n")
+		if _, err := LoadAnswers(path); err == nil {
+			t.Error("expected error for unknown key")
+		}
+	})
+
+	t.Run("unsupported extension", func(t *testing.T) {
+		path := write("answers.txt", "title: Fix login\n")
+		if _, err := LoadAnswers(path); err == nil {
+			t.Error("expected error for unsupported extension")
+		}
+	})
+}
+
+func TestAnswers_Validate_Config(t *testing.T) {
+	cfg := config.Default("")
+	cfg.ChangeTypes = []string{"Fix", "Feature"}
+	cfg.Sections = []string{config.SectionDescription}
+
+	if err := (Answers{Title: "x", Types: []string{"fix"}, Description: "d"}).Validate(cfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err := Answers{Title: "x", Types: []string{"Bug fix"}, Testing: []string{"step"}}.Validate(cfg)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{`unknown change type "Bug fix"`, `section "testing" is not enabled`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q, got %q", want, err.Error())
+		}
+	}
+}
+
+func TestAnswers_Components(t *testing.T) {
+	cfg := config.Default("")
+	cfg.Components = []config.Component{{Name: "api", Paths: []string{"services/api"}}, {Name: "web", Paths: []string{"web"}}}
+
+	if err := (Answers{Title: "x", Types: []string{"Bug fix"}, Components: []string{"billing"}}).Validate(cfg); err == nil || !strings.Contains(err.Error(), `unknown component "billing"`) {
+		t.Errorf("expected an unknown component error, got %v", err)
+	}
+
+	entry := changelog.Entry{}
+	Answers{Title: "x", Types: []string{"Bug fix"}, Components: []string{"WEB", "api", "web"}}.apply(cfg, &entry)
+	if !reflect.DeepEqual(entry.Components, []string{"web", "api"}) {
+		t.Errorf("expected the configured names once each, got %v", entry.Components)
+	}
+}
+
+// captureStdout returns what fn prints.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		output, _ := io.ReadAll(r)
+		done <- string(output)
+	}()
+	fn()
+	w.Close()
+	return <-done
+}
+
+func TestGenerateFromAnswers_Warnings(t *testing.T) {
+	initBranches(t, "main", "feature/b")
+	os.WriteFile("b.go", []byte("package b\n"), 0644)
+	for _, args := range [][]string{{"add", "b.go"}, {"-c", "user.name=tester", "-c", "user.email=tester@example.com", "commit", "-q", "-m", "feat: add b"}} {
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	cwd, _ := os.Getwd()
+	cfg := config.Default(cwd)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = GenerateFromAnswers(cfg, Answers{Title: "Add b", Types: []string{"Bug fix"}, TargetBranch: "main"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(output, `"New feature" is not selected but commit`) {
+		t.Errorf("expected the change type conflict to be pointed out, got\n%s", output)
+	}
+}
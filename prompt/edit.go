@@ -0,0 +1,270 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/abirhasanmubin/changelog-go/changelog"
+	"github.com/abirhasanmubin/changelog-go/config"
+	"github.com/abirhasanmubin/changelog-go/input"
+)
+
+// Sections Edit can jump to, besides the optional config sections.
+const (
+	SectionTitle      = "title"
+	SectionTypes      = "types"
+	SectionComponents = "components"
+	SectionChecklist  = "checklist"
+)
+
+// Predefined errors
+var (
+	UnknownSectionError = errors.New("unknown section")
+)
+
+// EditSections lists every section name Edit accepts, in wizard order.
+func EditSections() []string {
+	sections := []string{SectionTypes, SectionComponents, SectionTitle}
+	sections = append(sections, config.DefaultSections...)
+	return append(sections, SectionChecklist)
+}
+
+var sectionLabels = map[string]string{
+	config.SectionMotivation:   "motivation",
+	config.SectionDescription:  "description",
+	config.SectionTodos:        "instructions before merge",
+	config.SectionModelChanges: "model changes",
+	config.SectionTesting:      "testing steps",
+}
+
+// Edit re-opens the entry at path and asks every question again with the
+// saved answers as defaults, or only the one for section when it is set.
+// The commit list is refreshed and the file is rewritten in place.
+func Edit(cfg config.Config, path, section string) error {
+	if section != "" && !contains(EditSections(), section) {
+		return fmt.Errorf("%w %q, expected one of %s", UnknownSectionError, section, strings.Join(EditSections(), ", "))
+	}
+
+	entry, selectedTypes, err := loadForEdit(cfg, path)
+	if err != nil {
+		return err
+	}
+	prompter := input.NewHandler()
+	setupColors()
+
+	fmt.Printf("%s--- Editing %s ---%s\n", colorHeader, entry.Filename, colorReset)
+	fmt.Printf("%sPress ENTER to keep the current answers.%s\n\n", colorInfo, colorReset)
+	selectedTypes = editEntry(&entry, selectedTypes, prompter, cfg, section)
+
+	fmt.Printf("\n%s⏳ Refreshing git commit information...%s\n", colorWarn, colorReset)
+	targetBranch := entry.Metadata.TargetBranch
+	if targetBranch == "" {
+		targetBranch = promptTargetBranch(prompter)
+	}
+	refreshCommits(&entry, targetBranch)
+	warnStaleRemote()
+	warnChangeTypeConflicts(&entry, selectedTypes)
+	warnMissingChecklist(&entry)
+
+	return handleFileOutput(&entry, selectedTypes, filepath.Dir(path))
+}
+
+// loadForEdit parses the entry at path and carries it over to the current
+// configuration and repository.
+func loadForEdit(cfg config.Config, path string) (changelog.Entry, map[string]string, error) {
+	saved, selectedTypes, err := changelog.ParseFile(path)
+	if err != nil {
+		return changelog.Entry{}, nil, err
+	}
+	entry, err := newEntry(cfg)
+	if err != nil {
+		return changelog.Entry{}, nil, err
+	}
+
+	entry.Title = saved.Title
+	entry.Motivation = saved.Motivation
+	entry.Description = saved.Description
+	entry.Todos = saved.Todos
+	entry.ModelChanges = saved.ModelChanges
+	entry.Testing = saved.Testing
+	entry.Components = saved.Components
+	entry.Filename = saved.Filename
+	entry.ChangeTypes = mergeChangeTypes(cfg.ChangeTypes, saved.ChangeTypes, selectedTypes)
+	entry.Checklist = mergeChecklist(entry.Checklist, saved.Checklist)
+	if saved.Metadata.Branch != "" {
+		entry.Metadata.Branch = saved.Metadata.Branch
+	}
+	entry.Metadata.TargetBranch = saved.Metadata.TargetBranch
+	entry.Metadata.Commits = saved.Metadata.Commits
+	entry.Metadata.Files = saved.Metadata.Files
+	entry.Metadata.Tickets = saved.Metadata.Tickets
+	entry.Metadata.Contributors = saved.Metadata.Contributors
+	return entry, selectedTypes, nil
+}
+
+// mergeChangeTypes keeps the configured types and adds the saved types that
+// were selected but are no longer configured.
+func mergeChangeTypes(configured, saved []string, selectedTypes map[string]string) []string {
+	merged := append([]string(nil), configured...)
+	for _, changeType := range saved {
+		if selectedTypes[changeType] != "" && !contains(merged, changeType) {
+			merged = append(merged, changeType)
+		}
+	}
+	return merged
+}
+
+// mergeComponents keeps the configured components and adds the saved ones
+// that are no longer configured.
+func mergeComponents(configured, saved []string) []string {
+	merged := append([]string(nil), configured...)
+	for _, name := range saved {
+		if !contains(merged, name) {
+			merged = append(merged, name)
+		}
+	}
+	return merged
+}
+
+// mergeChecklist answers the configured checklist from the saved one,
+// matching items by id or text. Saved items no longer configured are kept.
+func mergeChecklist(configured, saved changelog.Checklist) changelog.Checklist {
+	merged := configured.Clone()
+	for _, item := range saved {
+		found := false
+		for i := range merged {
+			if (item.ID != "" && merged[i].ID == item.ID) || merged[i].Text == item.Text {
+				merged[i].Checked = item.Checked
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, item)
+		}
+	}
+	return merged
+}
+
+func editEntry(entry *changelog.Entry, selectedTypes map[string]string, prompter input.DefaultsPrompter, cfg config.Config, only string) map[string]string {
+	asks := func(section string) bool { return only == "" || only == section }
+
+	if asks(SectionTypes) {
+		if types, err := prompter.TakeMultiSelectInputWithDefaults("Select the type of changes", entry.ChangeTypes, selectedTypes); err == nil {
+			selectedTypes = types
+		}
+	}
+	if asks(SectionComponents) {
+		promptComponents(entry, prompter, mergeComponents(cfg.ComponentNames(), entry.Components), entry.Components)
+	}
+	if asks(SectionTitle) {
+		if title, err := prompter.TakeSingleLineInputWithDefault("Changelog title", entry.Title); err == nil {
+			entry.Title = title
+		}
+	}
+	for _, section := range cfg.Sections {
+		if asks(section) {
+			editSection(entry, prompter, section)
+		}
+	}
+	if only != "" && !cfg.HasSection(only) && sectionPrompts[only] != nil {
+		editSection(entry, prompter, only)
+	}
+	if asks(SectionChecklist) {
+		editChecklist(entry, prompter)
+	}
+	return selectedTypes
+}
+
+// editSection shows the saved answer for section and asks it again unless
+// it is kept.
+func editSection(entry *changelog.Entry, prompter input.Prompter, section string) {
+	current := sectionText(entry, section)
+	if current != "" {
+		fmt.Printf("%sCurrent %s:%s\n%s\n", colorInfo, sectionLabels[section], colorReset, current)
+		keep, err := prompter.TakeBooleanTypeInput(fmt.Sprintf("Keep the current %s?", sectionLabels[section]), true)
+		if err != nil || keep {
+			return
+		}
+		clearSection(entry, section)
+	}
+	sectionPrompts[section](entry, prompter)
+}
+
+func sectionText(entry *changelog.Entry, section string) string {
+	switch section {
+	case config.SectionMotivation:
+		return entry.Motivation
+	case config.SectionDescription:
+		return entry.Description
+	case config.SectionTodos:
+		return bulletList(entry.Todos)
+	case config.SectionModelChanges:
+		return bulletList(entry.ModelChanges)
+	case config.SectionTesting:
+		return bulletList(entry.Testing)
+	}
+	return ""
+}
+
+func clearSection(entry *changelog.Entry, section string) {
+	switch section {
+	case config.SectionMotivation:
+		entry.Motivation = ""
+	case config.SectionDescription:
+		entry.Description = ""
+	case config.SectionTodos:
+		entry.Todos = nil
+	case config.SectionModelChanges:
+		entry.ModelChanges = nil
+	case config.SectionTesting:
+		entry.Testing = nil
+	}
+}
+
+func bulletList(items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	return "- " + strings.Join(items, "\n- ")
+}
+
+// editChecklist asks every checklist item again, defaulting to its saved
+// answer.
+func editChecklist(entry *changelog.Entry, prompter input.Prompter) {
+	if len(entry.Checklist) == 0 {
+		return
+	}
+	fmt.Printf("%s? %sPlease complete the final checklist:%s\n", colorQuestion, colorBold, colorReset)
+	for i, item := range entry.Checklist {
+		if checked, err := prompter.TakeBooleanTypeInput(checklistQuestion(item), item.Checked); err == nil {
+			entry.Checklist[i].Checked = checked
+		}
+	}
+}
+
+// refreshCommits collects the commits, changed files, tickets and
+// contributors again, keeping the saved ones when git cannot list any
+// commits.
+func refreshCommits(entry *changelog.Entry, targetBranch string) {
+	saved := entry.Metadata
+	entry.PopulateCommitHistory(targetBranch)
+	if len(entry.Metadata.Commits) == 0 && len(saved.Commits) > 0 {
+		fmt.Printf("%s⚠ Could not refresh commits, keeping the saved list%s\n", colorWarn, colorReset)
+		entry.Metadata.Commits = saved.Commits
+		entry.Metadata.Files = saved.Files
+		entry.Metadata.Tickets = saved.Tickets
+		entry.Metadata.Contributors = saved.Contributors
+	}
+}
+
+func contains(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
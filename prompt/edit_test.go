@@ -0,0 +1,149 @@
+package prompt
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/abirhasanmubin/changelog-go/changelog"
+	"github.com/abirhasanmubin/changelog-go/config"
+)
+
+func (s *scriptedPrompter) TakeSingleLineInputWithDefault(question, defaultValue string) (string, error) {
+	line, _ := s.TakeSingleLineInput(question)
+	if line == "" {
+		return defaultValue, nil
+	}
+	return line, nil
+}
+
+func (s *scriptedPrompter) TakeMultiSelectInputWithDefaults(question string, options []string, defaults map[string]string) (map[string]string, error) {
+	if s.multiSelect == nil {
+		return defaults, nil
+	}
+	return s.multiSelect, nil
+}
+
+func writeSavedEntry(t *testing.T, entry changelog.Entry, selectedTypes map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "1700000000_user_feature-login.md")
+	if err := os.WriteFile(path, []byte(entry.GenerateMarkdown(selectedTypes)), 0644); err != nil {
+		t.Fatalf("failed to write entry: %v", err)
+	}
+	return path
+}
+
+func TestLoadForEdit(t *testing.T) {
+	saved := changelog.Entry{
+		Title:       "Fix login",
+		Description: "Refresh tokens",
+		Testing:     []string{"Log in"},
+		Components:  []string{"api"},
+		ChangeTypes: []string{"Bug fix", "Hotfix"},
+		Checklist: changelog.Checklist{
+			{ID: changelog.ChecklistSelfReview, Text: "I have performed a self-review of my code", Checked: true},
+			{Text: "Old item", Checked: true},
+		},
+		Metadata: changelog.Metadata{
+			Branch:       "feature/login",
+			TargetBranch: "main",
+			Commits:      []changelog.GitCommit{{Hash: "abc1234", Message: "Fix"}},
+		},
+	}
+	path := writeSavedEntry(t, saved, map[string]string{"Hotfix": "Hotfix"})
+
+	entry, selectedTypes, err := loadForEdit(config.Default(""), path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.Title != "Fix login" || entry.Description != "Refresh tokens" || entry.Filename != filepath.Base(path) || !reflect.DeepEqual(entry.Components, []string{"api"}) {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+	if selectedTypes["Hotfix"] != "Hotfix" {
+		t.Errorf("expected saved selection, got %v", selectedTypes)
+	}
+	wantTypes := append(append([]string(nil), changelog.DefaultChangeTypes...), "Hotfix")
+	if !reflect.DeepEqual(entry.ChangeTypes, wantTypes) {
+		t.Errorf("expected %v, got %v", wantTypes, entry.ChangeTypes)
+	}
+	if !entry.Checklist.Checked(changelog.ChecklistSelfReview) || entry.Checklist.Checked(changelog.ChecklistIncludesTesting) {
+		t.Errorf("expected saved checklist answers, got %+v", entry.Checklist)
+	}
+	if last := entry.Checklist[len(entry.Checklist)-1]; last.Text != "Old item" || !last.Checked {
+		t.Errorf("expected unconfigured saved items to be kept, got %+v", last)
+	}
+	if entry.Metadata.Branch != "feature/login" || entry.Metadata.TargetBranch != "main" || len(entry.Metadata.Commits) != 1 {
+		t.Errorf("unexpected metadata %+v", entry.Metadata)
+	}
+}
+
+func TestEditEntry_KeepsAnswers(t *testing.T) {
+	entry := changelog.Entry{
+		Title:       "Fix login",
+		Motivation:  "Users were logged out",
+		Testing:     []string{"Log in"},
+		ChangeTypes: changelog.DefaultChangeTypes,
+		Checklist:   changelog.DefaultChecklist(),
+	}
+	entry.Checklist.Set(changelog.ChecklistSelfReview, true)
+	selected := map[string]string{"Bug fix": "Bug fix"}
+
+	prompter := &scriptedPrompter{
+		lines: []string{""},
+		// keep motivation, description is empty so it is asked, no todos,
+		// no model changes, replace testing and add a step, then the checklist
+		booleans:     []bool{true, false, false, false, true, true, true, false, false, false},
+		multiLines:   []string{"Refresh tokens"},
+		instructions: [][]string{{"Log in", "Wait an hour"}},
+	}
+	types := editEntry(&entry, selected, prompter, config.Default(""), "")
+
+	if !reflect.DeepEqual(types, selected) {
+		t.Errorf("expected selection to be kept, got %v", types)
+	}
+	if entry.Title != "Fix login" || entry.Motivation != "Users were logged out" || entry.Description != "Refresh tokens" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+	if !reflect.DeepEqual(entry.Testing, []string{"Log in", "Wait an hour"}) {
+		t.Errorf("expected replaced testing steps, got %v", entry.Testing)
+	}
+	if !entry.Checklist.Checked(changelog.ChecklistIncludesTesting) {
+		t.Error("expected checklist to be asked again")
+	}
+}
+
+func TestEditEntry_SingleSection(t *testing.T) {
+	entry := changelog.Entry{Title: "Fix login", Testing: []string{"Log in"}, Checklist: changelog.DefaultChecklist()}
+	prompter := &scriptedPrompter{
+		booleans:     []bool{false, true},
+		instructions: [][]string{{"Log in", "Log out"}},
+	}
+	editEntry(&entry, nil, prompter, config.Default(""), config.SectionTesting)
+
+	if !reflect.DeepEqual(entry.Testing, []string{"Log in", "Log out"}) {
+		t.Errorf("unexpected testing steps %v", entry.Testing)
+	}
+	if len(prompter.booleans) != 0 || entry.Title != "Fix login" {
+		t.Error("expected only the testing section to be asked")
+	}
+}
+
+func TestEditEntry_Components(t *testing.T) {
+	cfg := config.Default("")
+	cfg.Components = []config.Component{{Name: "api", Paths: []string{"services/api"}}, {Name: "web", Paths: []string{"web"}}}
+	entry := changelog.Entry{Title: "Fix login", Components: []string{"legacy"}}
+	prompter := &scriptedPrompter{multiSelect: map[string]string{"api": "api", "web": "", "legacy": "legacy"}}
+
+	editEntry(&entry, nil, prompter, cfg, SectionComponents)
+	if !reflect.DeepEqual(entry.Components, []string{"api", "legacy"}) {
+		t.Errorf("expected saved components that are no longer configured to stay selectable, got %v", entry.Components)
+	}
+}
+
+func TestEdit_UnknownSection(t *testing.T) {
+	if err := Edit(config.Default(""), "entry.md", "notes"); !errors.Is(err, UnknownSectionError) {
+		t.Errorf("expected UnknownSectionError, got %v", err)
+	}
+}
@@ -4,42 +4,100 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/abirhasanmubin/changelog-go/changelog"
 	"github.com/abirhasanmubin/changelog-go/command"
+	"github.com/abirhasanmubin/changelog-go/config"
 	"github.com/abirhasanmubin/changelog-go/input"
+	"github.com/abirhasanmubin/changelog-go/ui"
 	"github.com/abirhasanmubin/changelog-go/utils"
 )
 
-const (
-	colorHeader  = "\033[36m\033[1m"
-	colorInfo    = "\033[2m"
-	colorWarn    = "\033[33m\033[1m"
-	colorSuccess = "\033[32m\033[1m"
-	colorError   = "\033[31m"
-	colorReset   = "\033[0m"
+var (
+	colorHeader   = "\033[36m\033[1m"
+	colorInfo     = "\033[2m"
+	colorWarn     = "\033[33m\033[1m"
+	colorSuccess  = "\033[32m\033[1m"
+	colorError    = "\033[31m"
+	colorQuestion = "\033[35m"
+	colorBold     = "\033[1m"
+	colorReset    = "\033[0m"
 )
 
-func Generate() {
-	entry := changelog.NewEntry()
+// setupColors turns colors off when output is not a terminal, or when the
+// input handler already did.
+func setupColors() {
+	if !ui.IsTerminal(os.Stdout) {
+		ui.DisableColors()
+	}
+	if ui.ColorsEnabled() {
+		return
+	}
+	for _, color := range []*string{&colorHeader, &colorInfo, &colorWarn, &colorSuccess, &colorError, &colorQuestion, &colorBold, &colorReset} {
+		*color = ""
+	}
+}
+
+// Generate runs the interactive wizard with the repository configuration
+// and writes the entry in the chosen output format.
+func Generate() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	return GenerateWithConfig(cfg)
+}
+
+// GenerateWithConfig runs the interactive wizard with cfg.
+func GenerateWithConfig(cfg config.Config) error {
 	prompter := input.NewHandler()
+	setupColors()
 
 	printHeader()
+	promptRemote(prompter)
+	entry, err := newEntry(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Git operations come first so the commits can suggest answers
+	targetBranch := promptTargetBranch(prompter)
+	fmt.Printf("%s⏳ Collecting git commit information...%s\n\n", colorWarn, colorReset)
+	entry.PopulateCommitHistory(targetBranch)
+	warnStaleRemote()
 
 	// Collect all information
-	selectedTypes := promptChangeTypes(prompter)
+	selectedTypes := promptChangeTypes(prompter, cfg.ChangeTypes, entry.SuggestedChangeTypes())
+	warnChangeTypeConflicts(&entry, selectedTypes)
+	promptComponents(&entry, prompter, cfg.ComponentNames(), entry.SuggestedComponents())
 	promptBasicInfo(&entry, prompter)
-	promptOptionalSections(&entry, prompter)
+	promptOptionalSections(&entry, prompter, cfg.Sections)
 	promptChecklist(&entry, prompter)
-
-	// Git operations
-	fmt.Printf("\n%s⏳ Collecting git commit information...%s\n", colorWarn, colorReset)
-	targetBranch := promptTargetBranch(prompter)
-	entry.PopulateCommitHistory(targetBranch)
+	warnMissingChecklist(&entry)
 
 	// Generate output
 	outputFormat := promptOutputFormat(prompter)
-	handleOutput(&entry, selectedTypes, outputFormat)
+	return handleOutput(&entry, selectedTypes, outputFormat, cfg.EntryDir())
+}
+
+// newEntry returns an entry carrying the options and templates cfg asks for.
+func newEntry(cfg config.Config) (changelog.Entry, error) {
+	templates, err := cfg.LoadTemplates()
+	if err != nil {
+		return changelog.Entry{}, err
+	}
+	entry := changelog.NewEntry()
+	entry.ChangeTypes = cfg.ChangeTypes
+	entry.Checklist = cfg.Checklist.Clone()
+	entry.Templates = templates
+	entry.FrontMatterFormat = cfg.Output.FrontMatter
+	entry.FileList = cfg.FileList()
+	entry.TicketPatterns = cfg.TicketPatterns()
+	entry.ContributorList = cfg.ContributorList()
+	entry.ComponentPaths = cfg.ComponentPaths()
+	entry.ExcludedPaths = cfg.ExcludedPaths()
+	return entry, nil
 }
 
 func printHeader() {
@@ -47,62 +105,114 @@ func printHeader() {
 	fmt.Printf("%sPlease answer the following questions to generate the changelog.%s\n\n", colorInfo, colorReset)
 }
 
-func promptOptionalSections(entry *changelog.Entry, prompter input.Prompter) {
-	promptMotivation(entry, prompter)
-	promptDescription(entry, prompter)
-	promptInstructions(entry, prompter)
-	promptModelChanges(entry, prompter)
-	promptTesting(entry, prompter)
+var sectionPrompts = map[string]func(*changelog.Entry, input.Prompter){
+	config.SectionMotivation:   promptMotivation,
+	config.SectionDescription:  promptDescription,
+	config.SectionTodos:        promptInstructions,
+	config.SectionModelChanges: promptModelChanges,
+	config.SectionTesting:      promptTesting,
 }
 
-func handleOutput(entry *changelog.Entry, selectedTypes map[string]string, outputFormat string) {
+func promptOptionalSections(entry *changelog.Entry, prompter input.Prompter, sections []string) {
+	for _, section := range sections {
+		if promptSection, ok := sectionPrompts[section]; ok {
+			promptSection(entry, prompter)
+		}
+	}
+}
+
+func handleOutput(entry *changelog.Entry, selectedTypes map[string]string, outputFormat, dir string) error {
 	switch outputFormat {
 	case "Generate file":
-		handleFileOutput(entry, selectedTypes)
+		return handleFileOutput(entry, selectedTypes, dir)
 	case "Copy Bitbucket PR text":
-		handleClipboardOutput(entry, selectedTypes)
+		return handleClipboardOutput(entry, selectedTypes)
 	case "Show Bitbucket PR text":
-		handleDisplayOutput(entry, selectedTypes)
+		return handleDisplayOutput(entry, selectedTypes)
 	}
+	return nil
 }
 
-func handleFileOutput(entry *changelog.Entry, selectedTypes map[string]string) {
-	cwd, err := os.Getwd()
-	if err != nil {
-		fmt.Printf("%sError getting current directory: %v%s\n", colorError, err, colorReset)
-		return
-	}
-	filePath := filepath.Join(cwd, ".logs", ".changelog")
-	if err := entry.SaveToFile(selectedTypes, filePath); err != nil {
-		fmt.Printf("%sError saving changelog: %v%s\n", colorError, err, colorReset)
-		return
+func handleFileOutput(entry *changelog.Entry, selectedTypes map[string]string, dir string) error {
+	if err := entry.SaveToFile(selectedTypes, dir); err != nil {
+		return fmt.Errorf("error saving changelog: %w", err)
 	}
-	fmt.Printf("\n%s✅ Success! Changelog generated at: %s/%s%s\n", colorSuccess, filePath, entry.Filename, colorReset)
+	fmt.Printf("\n%s✅ Success! Changelog generated at: %s%s\n", colorSuccess, filepath.Join(dir, entry.Filename), colorReset)
+	return nil
 }
 
-func handleClipboardOutput(entry *changelog.Entry, selectedTypes map[string]string) {
-	prContent := entry.GenerateBitbucketPR(selectedTypes)
+func handleClipboardOutput(entry *changelog.Entry, selectedTypes map[string]string) error {
+	prContent, err := entry.Render(changelog.TemplatePR, selectedTypes)
+	if err != nil {
+		return fmt.Errorf("error rendering PR text: %w", err)
+	}
 	if err := utils.CopyToClipboard(prContent); err != nil {
 		fmt.Printf("%sError copying to clipboard: %v%s\n", colorError, err, colorReset)
 		fmt.Printf("\n%sBitbucket PR content:%s\n\n%s\n", colorWarn, colorReset, prContent)
 	} else {
 		fmt.Printf("\n%s✅ Success! Bitbucket PR content copied to clipboard!%s\n", colorSuccess, colorReset)
 	}
+	printPullRequestLink(entry)
+	return nil
 }
 
-func handleDisplayOutput(entry *changelog.Entry, selectedTypes map[string]string) {
-	prContent := entry.GenerateBitbucketPR(selectedTypes)
+func handleDisplayOutput(entry *changelog.Entry, selectedTypes map[string]string) error {
+	prContent, err := entry.Render(changelog.TemplatePR, selectedTypes)
+	if err != nil {
+		return fmt.Errorf("error rendering PR text: %w", err)
+	}
 	fmt.Printf("\n%sBitbucket PR Content:%s\n\n%s\n", colorWarn, colorReset, prContent)
+	printPullRequestLink(entry)
+	return nil
+}
+
+func printPullRequestLink(entry *changelog.Entry) {
+	if entry.Metadata.PullRequestUrl != "" {
+		fmt.Printf("%sOpen the pull request: %s%s\n", colorInfo, entry.Metadata.PullRequestUrl, colorReset)
+	}
 }
 
-func promptChangeTypes(prompter input.Prompter) map[string]string {
-	changeTypes := []string{"Bug fix", "New feature", "Code refactor", "Breaking change", "Documentation update", "Other"}
-	selectedTypes, _ := prompter.TakeMultiSelectInput("Select the type of changes", changeTypes)
+// promptChangeTypes asks for the change types with the suggested ones
+// already selected.
+func promptChangeTypes(prompter input.DefaultsPrompter, changeTypes []string, suggested map[string]string) map[string]string {
+	selectedTypes, _ := prompter.TakeMultiSelectInputWithDefaults("Select the type of changes", changeTypes, suggested)
 	return selectedTypes
 }
 
-func promptBasicInfo(entry *changelog.Entry, prompter input.Prompter) {
-	entry.Title, _ = prompter.TakeSingleLineInput("Changelog title")
+// warnChangeTypeConflicts points out selected change types the commits
+// contradict.
+func warnChangeTypeConflicts(entry *changelog.Entry, selectedTypes map[string]string) {
+	for _, conflict := range entry.ChangeTypeConflicts(selectedTypes) {
+		fmt.Printf("%s⚠ %s%s\n", colorWarn, conflict, colorReset)
+	}
+}
+
+// promptComponents asks which components the change belongs to, with the
+// ones given selected. Nothing is asked without components.
+func promptComponents(entry *changelog.Entry, prompter input.DefaultsPrompter, components, selected []string) {
+	if len(components) == 0 {
+		return
+	}
+	defaults := make(map[string]string)
+	for _, name := range selected {
+		defaults[name] = name
+	}
+	answer, err := prompter.TakeMultiSelectInputWithDefaults("Select the components", components, defaults)
+	if err != nil {
+		return
+	}
+	entry.Components = nil
+	for _, name := range components {
+		if answer[name] != "" {
+			entry.Components = append(entry.Components, name)
+		}
+	}
+}
+
+// promptBasicInfo asks for the title, suggesting one from the commits or
+// the branch name.
+func promptBasicInfo(entry *changelog.Entry, prompter input.DefaultsPrompter) {
+	entry.Title, _ = prompter.TakeSingleLineInputWithDefault("Changelog title", entry.SuggestedTitle())
 }
 
 func promptMotivation(entry *changelog.Entry, prompter input.Prompter) {
@@ -134,18 +244,63 @@ func promptTesting(entry *changelog.Entry, prompter input.Prompter) {
 }
 
 func promptChecklist(entry *changelog.Entry, prompter input.Prompter) {
-	fmt.Printf("\033[35m? \033[1mPlease complete the final checklist:\033[0m\n")
-	entry.Checklist.SelfReview, _ = prompter.TakeBooleanTypeInput("I have performed a self-review of my code", true)
-	entry.Checklist.IncludesTesting, _ = prompter.TakeBooleanTypeInput("I have added tests that prove my fix is effective or my feature works", false)
-	entry.Checklist.Documentation, _ = prompter.TakeBooleanTypeInput("I have added necessary documentation (if appropriate)", false)
-	entry.Checklist.EngineerReachout, _ = prompter.TakeBooleanTypeInput("I have proactively reached out to an engineer to review this PR", false)
-	entry.Checklist.ReadmeUpdated, _ = prompter.TakeBooleanTypeInput("I have updated the README file (if appropriate)", false)
+	if entry.Checklist == nil {
+		entry.Checklist = changelog.DefaultChecklist()
+	}
+	if len(entry.Checklist) == 0 {
+		return
+	}
+	fmt.Printf("%s? %sPlease complete the final checklist:%s\n", colorQuestion, colorBold, colorReset)
+	for i, item := range entry.Checklist {
+		entry.Checklist[i].Checked, _ = prompter.TakeBooleanTypeInput(checklistQuestion(item), item.Default)
+	}
+}
+
+func checklistQuestion(item changelog.ChecklistItem) string {
+	if len(item.RequiredFor) > 0 {
+		return fmt.Sprintf("%s (required for %s)", item.Text, strings.Join(item.RequiredFor, ", "))
+	}
+	return item.Text
+}
+
+// warnMissingChecklist points out required checklist items left unchecked
+// for the chosen target branch.
+func warnMissingChecklist(entry *changelog.Entry) {
+	for _, item := range entry.Checklist.MissingFor(entry.Metadata.TargetBranch) {
+		fmt.Printf("%s⚠ Checklist item %q is required for '%s'%s\n", colorWarn, item.Text, entry.Metadata.TargetBranch, colorReset)
+	}
+}
+
+// promptRemote settles the remote for the run, asking when the
+// repository has several and none is configured or tracked.
+func promptRemote(prompter input.Prompter) {
+	if command.DefaultRemote != "" {
+		return
+	}
+	remote, candidates := command.New().DetectRemote()
+	if len(candidates) > 1 {
+		// The guess comes first so that it is the default.
+		options := []string{remote}
+		for _, candidate := range candidates {
+			if candidate != remote {
+				options = append(options, candidate)
+			}
+		}
+		if chosen, err := prompter.TakeSingleSelectInput("Select the remote to compare against", options); err == nil {
+			remote = chosen
+		}
+	}
+	command.DefaultRemote = remote
 }
 
 func promptTargetBranch(prompter input.Prompter) string {
-	cmd := command.Commands{Cmd: command.CommandRunner{}}
+	cmd := command.New()
 	branches, err := cmd.GetBranches()
-	if err != nil || len(branches) == 0 {
+	if err != nil {
+		fmt.Printf("%s⚠ Could not fetch branches (%s), skipping target branch selection%s\n", colorError, command.Reason(err), colorReset)
+		return ""
+	}
+	if len(branches) == 0 {
 		fmt.Printf("%s⚠ Could not fetch branches, skipping target branch selection%s\n", colorError, colorReset)
 		return ""
 	}
@@ -159,6 +314,22 @@ func promptTargetBranch(prompter input.Prompter) string {
 		}
 	}
 
+	// The detected branch comes first so that it is the default.
+	if detected := detectTargetBranch(cmd); detected != "" {
+		options := []string{detected}
+		for _, branch := range filteredBranches {
+			if branch != detected {
+				options = append(options, branch)
+			}
+		}
+		filteredBranches = options
+	}
+
+	if len(filteredBranches) == 0 {
+		fmt.Printf("%s⚠ No other branches found, skipping target branch selection%s\n", colorError, colorReset)
+		return ""
+	}
+
 	targetBranch, err := prompter.TakeSingleSelectInput("Select target source branch", filteredBranches)
 	if err != nil {
 		fmt.Printf("%s⚠ Error selecting target branch: %v%s\n", colorError, err, colorReset)
@@ -167,6 +338,23 @@ func promptTargetBranch(prompter input.Prompter) string {
 	return targetBranch
 }
 
+// detectTargetBranch guesses the target branch and says why.
+func detectTargetBranch(cmd command.Commands) string {
+	branch, reason := cmd.DetectTargetBranch()
+	if branch != "" {
+		fmt.Printf("%sDetected target branch '%s' (%s)%s\n", colorInfo, branch, reason, colorReset)
+	}
+	return branch
+}
+
+// warnStaleRemote says when branches and commits were read without a
+// successful fetch.
+func warnStaleRemote() {
+	if stale := command.DefaultFetcher.Stale(); stale != "" {
+		fmt.Printf("%s⚠ Branches and commits may be out of date: %s%s\n", colorWarn, stale, colorReset)
+	}
+}
+
 func promptOutputFormat(prompter input.Prompter) string {
 	outputOptions := []string{"Copy Bitbucket PR text", "Show Bitbucket PR text", "Generate file"}
 	selectedFormat, err := prompter.TakeSingleSelectInput("Select output format", outputOptions)
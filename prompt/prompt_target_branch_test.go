@@ -1,11 +1,44 @@
 package prompt
 
 import (
+	"os"
+	"os/exec"
 	"testing"
+
+	"github.com/abirhasanmubin/changelog-go/command"
 )
 
+// initBranches creates a repository with branches, the last one checked
+// out, and works in it for the duration of the test.
+func initBranches(t *testing.T, branches ...string) {
+	t.Helper()
+	dir := t.TempDir()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(cwd) })
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	command.DefaultFetcher.SetPolicy(command.FetchNever)
+	t.Cleanup(func() { command.DefaultFetcher.SetPolicy(command.FetchOnce) })
+
+	run := func(args ...string) {
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	run("init", "-q", "-b", branches[0])
+	run("-c", "user.name=tester", "-c", "user.email=tester@example.com", "commit", "-q", "--allow-empty", "-m", "init")
+	for _, branch := range branches[1:] {
+		run("checkout", "-q", "-b", branch)
+	}
+}
+
 func TestPromptTargetBranch(t *testing.T) {
 	t.Run("successful branch selection", func(t *testing.T) {
+		initBranches(t, "main", "feature/login")
 		mock := NewMockPrompter()
 		mock.SetResponse("TakeSingleSelectInput", "main")
 
@@ -19,6 +52,19 @@ func TestPromptTargetBranch(t *testing.T) {
 		}
 	})
 
+	t.Run("only the current branch", func(t *testing.T) {
+		initBranches(t, "main")
+		mock := NewMockPrompter()
+		mock.SetResponse("TakeSingleSelectInput", "main")
+
+		if result := promptTargetBranch(mock); result != "" {
+			t.Errorf("expected no target branch, got %q", result)
+		}
+		if mock.callCount["TakeSingleSelectInput"] != 0 {
+			t.Errorf("expected no prompt without other branches, got %d", mock.callCount["TakeSingleSelectInput"])
+		}
+	})
+
 	t.Run("error handling", func(t *testing.T) {
 		mock := NewMockPrompter()
 		mock.SetResponse("TakeSingleSelectInput", "develop")
@@ -2,6 +2,7 @@ package prompt
 
 import (
 	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/abirhasanmubin/changelog-go/changelog"
@@ -125,6 +126,14 @@ func (m *MockPrompter) TakeMultiSelectInput(question string, options []string) (
 	return result, nil
 }
 
+func (m *MockPrompter) TakeSingleLineInputWithDefault(question, defaultValue string) (string, error) {
+	return m.TakeSingleLineInput(question)
+}
+
+func (m *MockPrompter) TakeMultiSelectInputWithDefaults(question string, options []string, defaults map[string]string) (map[string]string, error) {
+	return m.TakeMultiSelectInput(question, options)
+}
+
 func (m *MockPrompter) TakeSingleSelectInput(question string, options []string) (string, error) {
 	m.callCount["TakeSingleSelectInput"]++
 	if resp, ok := m.responses["TakeSingleSelectInput"]; ok {
@@ -148,7 +157,7 @@ func TestPromptChangeTypes(t *testing.T) {
 	}
 	mock.SetResponse("TakeMultiSelectInput", expected)
 
-	result := promptChangeTypes(mock)
+	result := promptChangeTypes(mock, changelog.DefaultChangeTypes, nil)
 
 	if len(result) != len(expected) {
 		t.Errorf("expected %d items, got %d", len(expected), len(result))
@@ -323,19 +332,19 @@ func TestPromptChecklist(t *testing.T) {
 	entry := &changelog.Entry{}
 	promptChecklist(entry, booleanMock)
 
-	if !entry.Checklist.SelfReview {
+	if !entry.Checklist.Checked(changelog.ChecklistSelfReview) {
 		t.Error("expected SelfReview to be true")
 	}
-	if entry.Checklist.IncludesTesting {
+	if entry.Checklist.Checked(changelog.ChecklistIncludesTesting) {
 		t.Error("expected IncludesTesting to be false")
 	}
-	if !entry.Checklist.Documentation {
+	if !entry.Checklist.Checked(changelog.ChecklistDocumentation) {
 		t.Error("expected Documentation to be true")
 	}
-	if entry.Checklist.EngineerReachout {
+	if entry.Checklist.Checked(changelog.ChecklistEngineerReachout) {
 		t.Error("expected EngineerReachout to be false")
 	}
-	if !entry.Checklist.ReadmeUpdated {
+	if !entry.Checklist.Checked(changelog.ChecklistReadmeUpdated) {
 		t.Error("expected ReadmeUpdated to be true")
 	}
 	if booleanMock.callCount["TakeBooleanTypeInput"] != 5 {
@@ -348,7 +357,7 @@ func TestPromptChangeTypes_ErrorHandling(t *testing.T) {
 	mock.SetResponse("TakeMultiSelectInput", errors.New("input error"))
 
 	// The function ignores errors, so it should return nil map
-	result := promptChangeTypes(mock)
+	result := promptChangeTypes(mock, changelog.DefaultChangeTypes, nil)
 
 	// Should return nil when there's an error (error is ignored in the actual function)
 	if result != nil {
@@ -506,19 +515,51 @@ func TestPromptChecklist_ErrorHandling(t *testing.T) {
 	promptChecklist(entry, mock)
 
 	// All checklist items should remain false on error
-	if entry.Checklist.SelfReview {
+	if entry.Checklist.Checked(changelog.ChecklistSelfReview) {
 		t.Error("expected SelfReview to be false on error")
 	}
-	if entry.Checklist.IncludesTesting {
+	if entry.Checklist.Checked(changelog.ChecklistIncludesTesting) {
 		t.Error("expected IncludesTesting to be false on error")
 	}
-	if entry.Checklist.Documentation {
+	if entry.Checklist.Checked(changelog.ChecklistDocumentation) {
 		t.Error("expected Documentation to be false on error")
 	}
-	if entry.Checklist.EngineerReachout {
+	if entry.Checklist.Checked(changelog.ChecklistEngineerReachout) {
 		t.Error("expected EngineerReachout to be false on error")
 	}
-	if entry.Checklist.ReadmeUpdated {
+	if entry.Checklist.Checked(changelog.ChecklistReadmeUpdated) {
 		t.Error("expected ReadmeUpdated to be false on error")
 	}
 }
+
+func TestPromptChangeTypes_Suggested(t *testing.T) {
+	suggested := map[string]string{"Bug fix": "Bug fix", "New feature": ""}
+	prompter := &scriptedPrompter{}
+
+	if result := promptChangeTypes(prompter, []string{"Bug fix", "New feature"}, suggested); !reflect.DeepEqual(result, suggested) {
+		t.Errorf("expected the suggested types to be kept, got %v", result)
+	}
+}
+
+func TestPromptBasicInfo_SuggestedTitle(t *testing.T) {
+	entry := &changelog.Entry{Metadata: changelog.Metadata{Branch: "feature/add-login"}}
+	promptBasicInfo(entry, &scriptedPrompter{lines: []string{""}})
+
+	if entry.Title != "Add login" {
+		t.Errorf("expected the suggested title, got %q", entry.Title)
+	}
+}
+
+func TestPromptComponents(t *testing.T) {
+	entry := &changelog.Entry{}
+	promptComponents(entry, &scriptedPrompter{}, []string{"api", "web", "billing"}, []string{"billing", "api"})
+	if !reflect.DeepEqual(entry.Components, []string{"api", "billing"}) {
+		t.Errorf("expected the suggested components in config order, got %v", entry.Components)
+	}
+
+	entry = &changelog.Entry{Components: []string{"api"}}
+	promptComponents(entry, &scriptedPrompter{multiSelect: map[string]string{"api": ""}}, nil, nil)
+	if !reflect.DeepEqual(entry.Components, []string{"api"}) {
+		t.Errorf("expected nothing to be asked without components, got %v", entry.Components)
+	}
+}
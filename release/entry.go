@@ -0,0 +1,26 @@
+package release
+
+import (
+	"github.com/abirhasanmubin/changelog-go/changelog"
+)
+
+// readEntry reads the title, the selected change types, the tickets, the
+// contributors and the components of a saved entry. An entry without a
+// selected change type is of UntypedChangeType.
+func readEntry(path string) (Entry, error) {
+	parsed, selectedTypes, err := changelog.ParseFile(path)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry := Entry{Title: parsed.Title, Tickets: parsed.Metadata.Tickets, Contributors: parsed.Metadata.Contributors, Components: parsed.Components}
+	for _, changeType := range parsed.ChangeTypeOptions(selectedTypes) {
+		if changeType.Selected {
+			entry.Types = append(entry.Types, changeType)
+		}
+	}
+	if len(entry.Types) == 0 {
+		entry.Types = []changelog.ChangeType{{Name: UntypedChangeType, Selected: true}}
+	}
+	return entry, nil
+}
@@ -0,0 +1,508 @@
+// Package release compiles unreleased changelog entries into a Keep a
+// Changelog style CHANGELOG.md and archives the entries it consumed.
+package release
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/abirhasanmubin/changelog-go/changelog"
+)
+
+// DefaultFile is the changelog releases are written to, relative to the
+// repository root.
+const DefaultFile = "CHANGELOG.md"
+
+// ArchiveDir is the directory, inside the entry directory, that released
+// entries are moved to. Each release gets its own subdirectory.
+const ArchiveDir = changelog.ArchiveDir
+
+const header = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/).
+`
+
+// Predefined errors
+var (
+	InvalidVersionError = errors.New("invalid version")
+	NoEntriesError      = errors.New("no unreleased entries")
+	VersionExistsError  = errors.New("version already released")
+)
+
+// How a release lays out the entries of a monorepo's components.
+const (
+	// ComponentsGrouped writes one section, grouped by component.
+	ComponentsGrouped = "grouped"
+	// ComponentsSplit writes each component's entries to its own changelog.
+	ComponentsSplit = "split"
+)
+
+// ComponentModes lists every way of releasing components.
+var ComponentModes = []string{ComponentsGrouped, ComponentsSplit}
+
+// GeneralComponent is the heading of the entries that belong to no
+// component, when others do.
+const GeneralComponent = "General"
+
+// UntypedChangeType is the group of entries with no change type selected,
+// such as hand-written ones, so that every released entry is listed.
+const UntypedChangeType = "Other"
+
+var versionPattern = regexp.MustCompile(`^v?(\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?)$`)
+
+// Options describe a release.
+type Options struct {
+	// Version is the release version, with or without a leading "v".
+	Version string
+	Date    time.Time
+	// EntryDir holds the unreleased entries.
+	EntryDir string
+	// File is the changelog the release section is added to.
+	File string
+	// ChangeTypes orders the groups of the release section.
+	ChangeTypes []string
+	// DryRun builds the section without writing or archiving anything.
+	DryRun bool
+	// Tickets, when set, release only the entries with a ticket matching
+	// one of these globs, such as PAY-*, and leave the rest unreleased.
+	Tickets []string
+	// Components order the component groups of the release section.
+	Components []Component
+	// Split writes the entries of each component with a File to that file
+	// instead, leaving File the entries of no such component.
+	Split bool
+}
+
+// Component is a monorepo component as far as a release is concerned.
+type Component struct {
+	Name string
+	// File is the component's own changelog, for split releases.
+	File string
+}
+
+// Changelog is a changelog file a release adds a section to.
+type Changelog struct {
+	File    string
+	Section string
+	entries []Entry
+}
+
+// Result describes a finished release.
+type Result struct {
+	Version string
+	// Section is the release notes for every entry, grouped by component.
+	Section string
+	// Changelogs are the files the release adds a section to, each with
+	// its own section.
+	Changelogs []Changelog
+	// Entries are the consumed entry files, relative to EntryDir.
+	Entries []string
+	// ArchivedTo is where the entries were moved, empty on a dry run.
+	ArchivedTo string
+}
+
+// Entry is an unreleased entry as far as a release is concerned.
+type Entry struct {
+	File  string
+	Title string
+	// Types are the selected change types, "Other" carrying its detail.
+	Types        []changelog.ChangeType
+	Tickets      []changelog.Ticket
+	Contributors []changelog.Contributor
+	Components   []string
+}
+
+// Run compiles every unreleased entry into a new section of opts.File, or
+// of the components' own changelogs on a split release, and moves the
+// entries into the archive directory. Nothing is written when any of the
+// changelogs already has the version, and the changelogs and entries are
+// put back when a changelog cannot be written or an entry archived.
+func Run(opts Options) (Result, error) {
+	version, err := normalizeVersion(opts.Version)
+	if err != nil {
+		return Result{}, err
+	}
+
+	entries, err := Unreleased(opts.EntryDir)
+	if err != nil {
+		return Result{}, err
+	}
+	if len(opts.Tickets) > 0 {
+		if entries = FilterTickets(entries, opts.Tickets); len(entries) == 0 {
+			return Result{}, fmt.Errorf("%w with tickets matching %s", NoEntriesError, strings.Join(opts.Tickets, ", "))
+		}
+	}
+	var files []string
+	for _, entry := range entries {
+		files = append(files, entry.File)
+	}
+
+	result := Result{
+		Version:    version,
+		Section:    Section(version, opts.Date, opts.ChangeTypes, componentNames(opts.Components), entries),
+		Entries:    files,
+		Changelogs: changelogs(opts, entries),
+	}
+	existing := make([]string, len(result.Changelogs))
+	created := make([]bool, len(result.Changelogs))
+	contents := make([]string, len(result.Changelogs))
+	for i := range result.Changelogs {
+		target := &result.Changelogs[i]
+		content, err := os.ReadFile(target.File)
+		if err != nil && !os.IsNotExist(err) {
+			return Result{}, err
+		}
+		if hasVersion(string(content), version) {
+			return Result{}, fmt.Errorf("%w: %s is already in %s", VersionExistsError, version, target.File)
+		}
+		existing[i], created[i] = string(content), os.IsNotExist(err)
+		target.Section = Section(version, opts.Date, opts.ChangeTypes, componentNames(opts.Components), target.entries)
+		contents[i] = insertSection(existing[i], target.Section)
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	for _, target := range result.Changelogs {
+		if err := os.MkdirAll(filepath.Dir(target.File), 0755); err != nil {
+			return Result{}, fmt.Errorf("failed to create the directory of %s: %w", target.File, err)
+		}
+	}
+	for i, target := range result.Changelogs {
+		if err := os.WriteFile(target.File, []byte(contents[i]), 0644); err != nil {
+			restore(result.Changelogs[:i], existing, created)
+			return Result{}, fmt.Errorf("failed to write %s: %w", target.File, err)
+		}
+	}
+	result.ArchivedTo = filepath.Join(opts.EntryDir, ArchiveDir, version)
+	if err := archive(opts.EntryDir, result.ArchivedTo, files); err != nil {
+		restore(result.Changelogs, existing, created)
+		return Result{}, err
+	}
+	return result, nil
+}
+
+// changelogs assigns the entries to the files they are released to: all
+// of them to opts.File, or on a split release each component's entries to
+// its own file and the rest to opts.File. Entries written to a
+// component's file are no longer grouped by component there.
+func changelogs(opts Options, entries []Entry) []Changelog {
+	if !opts.Split {
+		return []Changelog{{File: opts.File, entries: entries}}
+	}
+	files := make(map[string]string)
+	for _, component := range opts.Components {
+		if component.File != "" {
+			files[component.Name] = component.File
+		}
+	}
+
+	var remaining []Entry
+	byComponent := make(map[string][]Entry)
+	for _, entry := range entries {
+		var unfiled []string
+		for _, name := range entry.Components {
+			if _, ok := files[name]; ok {
+				own := entry
+				own.Components = nil
+				byComponent[name] = append(byComponent[name], own)
+			} else {
+				unfiled = append(unfiled, name)
+			}
+		}
+		if len(entry.Components) == 0 || len(unfiled) > 0 {
+			entry.Components = unfiled
+			remaining = append(remaining, entry)
+		}
+	}
+
+	var targets []Changelog
+	if len(remaining) > 0 {
+		targets = append(targets, Changelog{File: opts.File, entries: remaining})
+	}
+	for _, component := range opts.Components {
+		if included := byComponent[component.Name]; len(included) > 0 {
+			targets = append(targets, Changelog{File: component.File, entries: included})
+		}
+	}
+	return targets
+}
+
+func componentNames(components []Component) []string {
+	names := make([]string, len(components))
+	for i, component := range components {
+		names[i] = component.Name
+	}
+	return names
+}
+
+// Unreleased reads every entry waiting in entryDir.
+func Unreleased(entryDir string) ([]Entry, error) {
+	files, err := changelog.ListEntryFiles(entryDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w in %s", NoEntriesError, entryDir)
+	}
+
+	var entries []Entry
+	for _, file := range files {
+		entry, err := readEntry(filepath.Join(entryDir, file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		entry.File = file
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// FilterTickets keeps the entries with a ticket matching one of patterns.
+func FilterTickets(entries []Entry, patterns []string) []Entry {
+	var kept []Entry
+	for _, entry := range entries {
+		for _, ticket := range entry.Tickets {
+			if changelog.MatchTicket(ticket.Key, patterns...) {
+				kept = append(kept, entry)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+// Section renders the release section for entries, grouped by change type
+// in the order of changeTypes. An entry is listed under every type it
+// selected, followed by its tickets. When entries belong to components,
+// the types are grouped under a heading per component, in the order of
+// components, with the entries of no component last under
+// GeneralComponent. The entries' contributors are thanked below the groups.
+func Section(version string, date time.Time, changeTypes, components []string, entries []Entry) string {
+	var section strings.Builder
+	section.WriteString(fmt.Sprintf("## [%s] - %s\n", version, date.Format("2006-01-02")))
+
+	if order := componentOrder(components, entries); len(order) == 0 {
+		writeGroups(&section, "###", changeTypes, entries)
+	} else {
+		for _, component := range order {
+			var included []Entry
+			for _, entry := range entries {
+				if belongsTo(entry, component) {
+					included = append(included, entry)
+				}
+			}
+			heading := component
+			if heading == "" {
+				heading = GeneralComponent
+			}
+			section.WriteString(fmt.Sprintf("\n### %s\n", heading))
+			writeGroups(&section, "####", changeTypes, included)
+		}
+	}
+
+	var contributors [][]changelog.Contributor
+	for _, entry := range entries {
+		contributors = append(contributors, entry.Contributors)
+	}
+	if thanks := changelog.MergeContributors(contributors...); len(thanks) > 0 {
+		section.WriteString(fmt.Sprintf("\nThanks to %s.\n", mentions(thanks)))
+	}
+	return section.String()
+}
+
+// writeGroups writes a group of entries per change type under level
+// headings.
+func writeGroups(section *strings.Builder, level string, changeTypes []string, entries []Entry) {
+	for _, group := range groupOrder(changeTypes, entries) {
+		var lines []string
+		for _, entry := range entries {
+			for _, changeType := range entry.Types {
+				if changeType.Name != group {
+					continue
+				}
+				line := "- " + entry.Title
+				if changeType.Detail != "" {
+					line += fmt.Sprintf(" (%s)", changeType.Detail)
+				}
+				if len(entry.Tickets) > 0 {
+					line += " (" + ticketLinks(entry.Tickets) + ")"
+				}
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		section.WriteString(fmt.Sprintf("\n%s %s\n\n", level, group))
+		section.WriteString(strings.Join(lines, "\n") + "\n")
+	}
+}
+
+// componentOrder returns the components the entries belong to, in the
+// order of components followed by those only the entries know about, and
+// "" last for entries of no component. It is empty when no entry belongs
+// to a component.
+func componentOrder(components []string, entries []Entry) []string {
+	used := make(map[string]bool)
+	var unknown []string
+	general := false
+	for _, entry := range entries {
+		if len(entry.Components) == 0 {
+			general = true
+		}
+		for _, name := range entry.Components {
+			if !used[name] && !contains(components, name) {
+				unknown = append(unknown, name)
+			}
+			used[name] = true
+		}
+	}
+	if len(used) == 0 {
+		return nil
+	}
+
+	var order []string
+	for _, name := range components {
+		if used[name] {
+			order = append(order, name)
+		}
+	}
+	order = append(order, unknown...)
+	if general {
+		order = append(order, "")
+	}
+	return order
+}
+
+func belongsTo(entry Entry, component string) bool {
+	if component == "" {
+		return len(entry.Components) == 0
+	}
+	return contains(entry.Components, component)
+}
+
+func contains(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// mentions lists contributors as "a, b and c".
+func mentions(contributors []changelog.Contributor) string {
+	names := make([]string, len(contributors))
+	for i, contributor := range contributors {
+		names[i] = contributor.Mention()
+	}
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+func ticketLinks(tickets []changelog.Ticket) string {
+	links := make([]string, len(tickets))
+	for i, ticket := range tickets {
+		links[i] = ticket.Key
+		if ticket.URL != "" {
+			links[i] = fmt.Sprintf("[%s](%s)", ticket.Key, ticket.URL)
+		}
+	}
+	return strings.Join(links, ", ")
+}
+
+// groupOrder returns changeTypes followed by any type only the entries know
+// about, such as types removed from the config since they were written.
+func groupOrder(changeTypes []string, entries []Entry) []string {
+	order := append([]string(nil), changeTypes...)
+	known := make(map[string]bool)
+	for _, changeType := range order {
+		known[changeType] = true
+	}
+	for _, entry := range entries {
+		for _, changeType := range entry.Types {
+			if !known[changeType.Name] {
+				known[changeType.Name] = true
+				order = append(order, changeType.Name)
+			}
+		}
+	}
+	return order
+}
+
+func normalizeVersion(version string) (string, error) {
+	match := versionPattern.FindStringSubmatch(strings.TrimSpace(version))
+	if match == nil {
+		return "", fmt.Errorf("%w %q, expected something like v1.4.0", InvalidVersionError, version)
+	}
+	return match[1], nil
+}
+
+func hasVersion(content, version string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "## ["+version+"]") {
+			return true
+		}
+	}
+	return false
+}
+
+// insertSection adds section above the most recent release, below the
+// header and any Unreleased section.
+func insertSection(content, section string) string {
+	if strings.TrimSpace(content) == "" {
+		return header + "\n" + section
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## [") && !strings.HasPrefix(strings.ToLower(line), "## [unreleased]") {
+			before := strings.Join(lines[:i], "")
+			return before + section + "\n" + strings.Join(lines[i:], "")
+		}
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + "\n" + section
+}
+
+// restore puts back the content targets had before the release, removing
+// the ones it created. It is best effort: the original error is what matters.
+func restore(targets []Changelog, existing []string, created []bool) {
+	for i, target := range targets {
+		if created[i] {
+			os.Remove(target.File)
+		} else {
+			os.WriteFile(target.File, []byte(existing[i]), 0644)
+		}
+	}
+}
+
+// archive moves files from entryDir to archiveDir. When one cannot be
+// moved, the ones already moved are put back.
+func archive(entryDir, archiveDir string, files []string) error {
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	for i, file := range files {
+		if err := os.Rename(filepath.Join(entryDir, file), filepath.Join(archiveDir, file)); err != nil {
+			for _, moved := range files[:i] {
+				os.Rename(filepath.Join(archiveDir, moved), filepath.Join(entryDir, moved))
+			}
+			os.Remove(archiveDir)
+			return fmt.Errorf("failed to archive %s: %w", file, err)
+		}
+	}
+	return nil
+}
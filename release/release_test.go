@@ -0,0 +1,444 @@
+package release
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abirhasanmubin/changelog-go/changelog"
+)
+
+var releaseDate = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+func writeEntry(t *testing.T, dir, name string, entry changelog.Entry, selectedTypes map[string]string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	content := entry.GenerateMarkdown(selectedTypes)
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write entry: %v", err)
+	}
+}
+
+func setup(t *testing.T) Options {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, ".logs", ".changelog")
+	writeEntry(t, dir, "1700000000_user_fix-login.md", changelog.Entry{Title: "Fix login redirect"}, map[string]string{"Bug fix": "Bug fix"})
+	writeEntry(t, dir, "1700000100_user_export.md", changelog.Entry{Title: "CSV export"}, map[string]string{"New feature": "New feature", "Breaking change": "Breaking change"})
+	writeEntry(t, dir, "1700000200_user_audit.md", changelog.Entry{Title: "Audit log"}, map[string]string{"Other": "Security"})
+	return Options{
+		Version:     "v1.4.0",
+		Date:        releaseDate,
+		EntryDir:    dir,
+		File:        filepath.Join(root, DefaultFile),
+		ChangeTypes: changelog.DefaultChangeTypes,
+	}
+}
+
+func TestRun(t *testing.T) {
+	opts := setup(t)
+
+	result, err := Run(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `## [1.4.0] - 2024-03-01
+
+### Bug fix
+
+- Fix login redirect
+
+### New feature
+
+- CSV export
+
+### Breaking change
+
+- CSV export
+
+### Other
+
+- Audit log (Security)
+`
+	if result.Section != want {
+		t.Errorf("unexpected section:\n%s\nwant:\n%s", result.Section, want)
+	}
+
+	content, err := os.ReadFile(opts.File)
+	if err != nil {
+		t.Fatalf("expected %s to be written: %v", opts.File, err)
+	}
+	if !strings.HasPrefix(string(content), "# Changelog\n") || !strings.HasSuffix(string(content), want) {
+		t.Errorf("unexpected changelog:\n%s", content)
+	}
+
+	remaining, _ := changelog.ListEntryFiles(opts.EntryDir)
+	if len(remaining) != 0 {
+		t.Errorf("expected entries to be archived, %v remain", remaining)
+	}
+	archived, _ := changelog.ListEntryFiles(filepath.Join(opts.EntryDir, ArchiveDir, "1.4.0"))
+	if len(archived) != 3 {
+		t.Errorf("expected 3 archived entries, got %v", archived)
+	}
+
+	if _, err := Run(opts); !errors.Is(err, NoEntriesError) {
+		t.Errorf("expected NoEntriesError on a second run, got %v", err)
+	}
+}
+
+func TestRun_UntypedEntry(t *testing.T) {
+	opts := setup(t)
+	opts.EntryDir = t.TempDir()
+	writeEntry(t, opts.EntryDir, "1700000000_user_fix-login.md", changelog.Entry{Title: "Fix login redirect"}, map[string]string{"Bug fix": "Bug fix"})
+	writeEntry(t, opts.EntryDir, "1700000100_user_notes.md", changelog.Entry{Title: "Release notes"}, nil)
+
+	result, err := Run(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lines := strings.Count(result.Section, "\n- "); lines != len(result.Entries) {
+		t.Errorf("expected a line per released entry, got %d for %d:\n%s", lines, len(result.Entries), result.Section)
+	}
+	if !strings.HasSuffix(result.Section, "### Other\n\n- Release notes\n") {
+		t.Errorf("expected the untyped entry under Other, got\n%s", result.Section)
+	}
+}
+
+func TestRun_DryRun(t *testing.T) {
+	opts := setup(t)
+	opts.DryRun = true
+
+	result, err := Run(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Section == "" || result.ArchivedTo != "" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if _, err := os.Stat(opts.File); !os.IsNotExist(err) {
+		t.Error("expected no changelog to be written")
+	}
+	if remaining, _ := changelog.ListEntryFiles(opts.EntryDir); len(remaining) != 3 {
+		t.Errorf("expected entries to be kept, got %v", remaining)
+	}
+}
+
+func TestRun_Tickets(t *testing.T) {
+	opts := setup(t)
+	writeEntry(t, opts.EntryDir, "1700000300_user_refund.md", changelog.Entry{
+		Title: "Refund flow",
+		Metadata: changelog.Metadata{Tickets: []changelog.Ticket{
+			{Key: "PAY-1234", URL: "https://jira.example.com/browse/PAY-1234"},
+			{Key: "#12"},
+		}},
+	}, map[string]string{"New feature": "New feature"})
+	opts.Tickets = []string{"pay-*"}
+
+	result, err := Run(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "## [1.4.0] - 2024-03-01\n\n### New feature\n\n- Refund flow ([PAY-1234](https://jira.example.com/browse/PAY-1234), #12)\n"
+	if result.Section != want {
+		t.Errorf("expected section\n%s\ngot\n%s", want, result.Section)
+	}
+	if remaining, _ := changelog.ListEntryFiles(opts.EntryDir); len(remaining) != 3 {
+		t.Errorf("expected entries without matching tickets to stay unreleased, got %v", remaining)
+	}
+
+	opts = setup(t)
+	opts.Tickets = []string{"OPS-*"}
+	if _, err := Run(opts); !errors.Is(err, NoEntriesError) {
+		t.Errorf("expected NoEntriesError, got %v", err)
+	}
+}
+
+func TestRun_Contributors(t *testing.T) {
+	opts := setup(t)
+	opts.DryRun = true
+	writeEntry(t, opts.EntryDir, "1700000300_user_refund.md", changelog.Entry{
+		Title: "Refund flow",
+		Metadata: changelog.Metadata{Contributors: []changelog.Contributor{
+			{Name: "Jane Doe", Handle: "jane"},
+			{Name: "Bob Stone"},
+		}},
+	}, map[string]string{"New feature": "New feature"})
+	writeEntry(t, opts.EntryDir, "1700000400_user_retry.md", changelog.Entry{
+		Title: "Retry refunds",
+		Metadata: changelog.Metadata{Contributors: []changelog.Contributor{
+			{Name: "Carol"},
+			{Handle: "jane"},
+		}},
+	}, map[string]string{"Bug fix": "Bug fix"})
+
+	result, err := Run(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(result.Section, "- Audit log (Security)\n\nThanks to @jane, Bob Stone and Carol.\n") {
+		t.Errorf("expected contributors to be thanked once each, got\n%s", result.Section)
+	}
+}
+
+func setupComponents(t *testing.T) Options {
+	t.Helper()
+	opts := setup(t)
+	writeEntry(t, opts.EntryDir, "1700000300_user_rate-limit.md", changelog.Entry{Title: "Rate limit logins", Components: []string{"api"}}, map[string]string{"New feature": "New feature"})
+	writeEntry(t, opts.EntryDir, "1700000400_user_session.md", changelog.Entry{Title: "Shared session cookie", Components: []string{"web", "api"}}, map[string]string{"Bug fix": "Bug fix"})
+	root := filepath.Dir(opts.File)
+	opts.Components = []Component{
+		{Name: "api", File: filepath.Join(root, "services", "api", DefaultFile)},
+		{Name: "web", File: filepath.Join(root, "services", "web", DefaultFile)},
+	}
+	for _, component := range opts.Components {
+		os.MkdirAll(filepath.Dir(component.File), 0755)
+	}
+	return opts
+}
+
+func TestRun_ComponentsGrouped(t *testing.T) {
+	opts := setupComponents(t)
+
+	result, err := Run(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `## [1.4.0] - 2024-03-01
+
+### api
+
+#### Bug fix
+
+- Shared session cookie
+
+#### New feature
+
+- Rate limit logins
+
+### web
+
+#### Bug fix
+
+- Shared session cookie
+
+### General
+
+#### Bug fix
+
+- Fix login redirect
+
+#### New feature
+
+- CSV export
+
+#### Breaking change
+
+- CSV export
+
+#### Other
+
+- Audit log (Security)
+`
+	if result.Section != want {
+		t.Errorf("unexpected section:\n%s\nwant:\n%s", result.Section, want)
+	}
+	if len(result.Changelogs) != 1 || result.Changelogs[0].File != opts.File {
+		t.Errorf("expected only %s to be written, got %+v", opts.File, result.Changelogs)
+	}
+	if _, err := os.Stat(opts.Components[0].File); !os.IsNotExist(err) {
+		t.Error("expected no component changelog to be written")
+	}
+}
+
+func TestRun_ComponentsSplit(t *testing.T) {
+	opts := setupComponents(t)
+	opts.Split = true
+	// Entries of components without their own changelog stay in the
+	// root one.
+	writeEntry(t, opts.EntryDir, "1700000500_user_docs.md", changelog.Entry{Title: "Runbook", Components: []string{"docs", "web"}}, map[string]string{"Documentation update": "Documentation update"})
+
+	result, err := Run(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{
+		opts.File:               "### docs\n\n#### Documentation update\n\n- Runbook\n\n### General\n\n#### Bug fix\n\n- Fix login redirect\n",
+		opts.Components[0].File: "## [1.4.0] - 2024-03-01\n\n### Bug fix\n\n- Shared session cookie\n\n### New feature\n\n- Rate limit logins\n",
+		opts.Components[1].File: "## [1.4.0] - 2024-03-01\n\n### Bug fix\n\n- Shared session cookie\n\n### Documentation update\n\n- Runbook\n",
+	}
+	if len(result.Changelogs) != len(want) {
+		t.Fatalf("expected %d changelogs, got %+v", len(want), result.Changelogs)
+	}
+	for _, target := range result.Changelogs {
+		content, err := os.ReadFile(target.File)
+		if err != nil {
+			t.Fatalf("expected %s to be written: %v", target.File, err)
+		}
+		if !strings.Contains(string(content), want[target.File]) || !strings.HasSuffix(string(content), target.Section) {
+			t.Errorf("unexpected %s:\n%s", target.File, content)
+		}
+	}
+	if archived, _ := changelog.ListEntryFiles(result.ArchivedTo); len(archived) != 6 {
+		t.Errorf("expected every entry to be archived once, got %v", archived)
+	}
+}
+
+func TestRun_ComponentsSplitVersionExists(t *testing.T) {
+	opts := setupComponents(t)
+	opts.Split = true
+	os.WriteFile(opts.Components[1].File, []byte(header+"\n## [1.4.0] - 2024-01-01\n"), 0644)
+
+	if _, err := Run(opts); !errors.Is(err, VersionExistsError) {
+		t.Errorf("expected VersionExistsError, got %v", err)
+	}
+	if _, err := os.Stat(opts.File); !os.IsNotExist(err) {
+		t.Error("expected nothing to be written")
+	}
+}
+
+func TestRun_ComponentsSplitCreatesDirectories(t *testing.T) {
+	opts := setupComponents(t)
+	opts.Split = true
+	root := filepath.Dir(opts.File)
+	opts.Components[0].File = filepath.Join(root, "packages", "api", DefaultFile)
+
+	if _, err := Run(opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(opts.Components[0].File); err != nil {
+		t.Errorf("expected %s to be written: %v", opts.Components[0].File, err)
+	}
+}
+
+func TestRun_ComponentsSplitWriteFails(t *testing.T) {
+	opts := setupComponents(t)
+	opts.Split = true
+	previous := header + "\n## [1.3.0] - 2024-01-01\n"
+	os.WriteFile(opts.Components[0].File, []byte(previous), 0644)
+	// A directory in place of the last changelog makes its write fail.
+	os.MkdirAll(opts.Components[1].File, 0755)
+
+	if _, err := Run(opts); err == nil {
+		t.Fatal("expected the write to fail")
+	}
+	if _, err := os.Stat(opts.File); !os.IsNotExist(err) {
+		t.Error("expected the created root changelog to be removed")
+	}
+	if content, _ := os.ReadFile(opts.Components[0].File); string(content) != previous {
+		t.Errorf("expected the api changelog to be put back, got\n%s", content)
+	}
+	if files, _ := changelog.ListEntryFiles(opts.EntryDir); len(files) != 5 {
+		t.Errorf("expected every entry to stay unreleased, got %v", files)
+	}
+}
+
+func TestRun_ArchiveFails(t *testing.T) {
+	opts := setup(t)
+	previous := header + "\n## [1.3.0] - 2024-01-01\n"
+	os.WriteFile(opts.File, []byte(previous), 0644)
+	// A directory in place of the last archived entry makes its move fail.
+	os.MkdirAll(filepath.Join(opts.EntryDir, ArchiveDir, "1.4.0", "1700000200_user_audit.md", "taken"), 0755)
+
+	if _, err := Run(opts); err == nil {
+		t.Fatal("expected archiving to fail")
+	}
+	if content, _ := os.ReadFile(opts.File); string(content) != previous {
+		t.Errorf("expected the changelog to be put back, got\n%s", content)
+	}
+	if files, _ := changelog.ListEntryFiles(opts.EntryDir); len(files) != 3 {
+		t.Errorf("expected every entry to be moved back, got %v", files)
+	}
+	if _, err := Run(opts); err == nil || errors.Is(err, VersionExistsError) {
+		t.Errorf("expected a rerun to fail on the archive again rather than the version, got %v", err)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	t.Run("invalid version", func(t *testing.T) {
+		opts := setup(t)
+		opts.Version = "next"
+		if _, err := Run(opts); !errors.Is(err, InvalidVersionError) {
+			t.Errorf("expected InvalidVersionError, got %v", err)
+		}
+	})
+
+	t.Run("version exists", func(t *testing.T) {
+		opts := setup(t)
+		os.WriteFile(opts.File, []byte(header+"\n## [1.4.0] - 2024-01-01\n"), 0644)
+		if _, err := Run(opts); !errors.Is(err, VersionExistsError) {
+			t.Errorf("expected VersionExistsError, got %v", err)
+		}
+	})
+
+	t.Run("no entries", func(t *testing.T) {
+		opts := setup(t)
+		opts.EntryDir = t.TempDir()
+		if _, err := Run(opts); !errors.Is(err, NoEntriesError) {
+			t.Errorf("expected NoEntriesError, got %v", err)
+		}
+	})
+}
+
+func TestInsertSection(t *testing.T) {
+	section := "## [1.1.0] - 2024-03-01\n\n### Bug fix\n\n- Fix\n"
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "empty file gets a header",
+			content: "",
+			want:    header + "\n" + section,
+		},
+		{
+			name:    "above the previous release",
+			content: "# Changelog\n\n## [1.0.0] - 2024-01-01\n\n- Initial\n",
+			want:    "# Changelog\n\n" + section + "\n## [1.0.0] - 2024-01-01\n\n- Initial\n",
+		},
+		{
+			name:    "below the unreleased section",
+			content: "# Changelog\n\n## [Unreleased]\n\n- WIP\n\n## [1.0.0] - 2024-01-01\n",
+			want:    "# Changelog\n\n## [Unreleased]\n\n- WIP\n\n" + section + "\n## [1.0.0] - 2024-01-01\n",
+		},
+		{
+			name:    "header only",
+			content: "# Changelog",
+			want:    "# Changelog\n\n" + section,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := insertSection(tt.content, section); got != tt.want {
+				t.Errorf("expected:\n%q\ngot:\n%q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+		wantErr bool
+	}{
+		{"v1.4.0", "1.4.0", false},
+		{"1.4.0", "1.4.0", false},
+		{"v2.0.0-rc.1", "2.0.0-rc.1", false},
+		{"1.4", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := normalizeVersion(tt.version)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("normalizeVersion(%q) = %q, %v", tt.version, got, err)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package release
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultTagPrefix is put in front of versions in tag names when the
+// repository has no version tags yet.
+const DefaultTagPrefix = "v"
+
+var preReleasePattern = regexp.MustCompile(`^[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*$`)
+
+// Version is a semantic version.
+type Version struct {
+	Major, Minor, Patch int
+	// Pre is the pre-release, such as "rc.1".
+	Pre string
+	// Build is the build metadata, ignored when comparing.
+	Build string
+}
+
+// ParseVersion reads a semantic version, with or without a leading "v".
+func ParseVersion(version string) (Version, error) {
+	normalized, err := normalizeVersion(version)
+	if err != nil {
+		return Version{}, err
+	}
+	core, build, _ := strings.Cut(normalized, "+")
+	core, pre, _ := strings.Cut(core, "-")
+	parts := strings.Split(core, ".")
+
+	v := Version{Pre: pre, Build: build}
+	for i, target := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		if *target, err = strconv.Atoi(parts[i]); err != nil {
+			return Version{}, fmt.Errorf("%w %q: %v", InvalidVersionError, version, err)
+		}
+	}
+	return v, nil
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 as v sorts before, with or after other, by
+// semantic version precedence.
+func (v Version) Compare(other Version) int {
+	for _, diff := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if diff != 0 {
+			return sign(diff)
+		}
+	}
+	switch {
+	case v.Pre == other.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case other.Pre == "":
+		return -1
+	}
+
+	ours, theirs := strings.Split(v.Pre, "."), strings.Split(other.Pre, ".")
+	for i := 0; i < len(ours) && i < len(theirs); i++ {
+		if c := compareIdentifier(ours[i], theirs[i]); c != 0 {
+			return c
+		}
+	}
+	return sign(len(ours) - len(theirs))
+}
+
+// compareIdentifier orders pre-release identifiers: numeric ones
+// numerically and below alphanumeric ones, which compare as text.
+func compareIdentifier(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return sign(an - bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// Bump is the part of a version a release increments.
+type Bump int
+
+const (
+	BumpNone Bump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+func (b Bump) String() string {
+	switch b {
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// Change types that decide the bump. Every other type is a patch.
+const (
+	breakingChangeType = "Breaking change"
+	newFeatureType     = "New feature"
+)
+
+// BumpFor returns the bump entries call for: major for a breaking change,
+// minor for a new feature and patch for anything else.
+func BumpFor(entries []Entry) Bump {
+	bump := BumpNone
+	for _, entry := range entries {
+		entryBump := BumpPatch
+		for _, changeType := range entry.Types {
+			switch {
+			case changeType.Name == breakingChangeType:
+				entryBump = BumpMajor
+			case changeType.Name == newFeatureType && entryBump < BumpMinor:
+				entryBump = BumpMinor
+			}
+		}
+		if entryBump > bump {
+			bump = entryBump
+		}
+	}
+	return bump
+}
+
+// Next returns the version after v for bump. With a pre-release identifier
+// such as "rc" the result is a pre-release of that version, numbered after
+// v when v is already one: 1.4.0-rc.1 becomes 1.4.0-rc.2. A result that
+// would not come after v, such as beta after rc, is an InvalidVersionError.
+func (v Version) Next(bump Bump, pre string) (Version, error) {
+	if pre != "" && !preReleasePattern.MatchString(pre) {
+		return Version{}, fmt.Errorf("%w: bad pre-release identifier %q", InvalidVersionError, pre)
+	}
+
+	next := Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	// A pre-release already counts as the bump up to its own version.
+	if v.Pre == "" || !reaches(v, bump) {
+		switch bump {
+		case BumpMajor:
+			next = Version{Major: v.Major + 1}
+		case BumpMinor:
+			next = Version{Major: v.Major, Minor: v.Minor + 1}
+		case BumpPatch:
+			next.Patch++
+		}
+	}
+	if pre == "" {
+		return next, nil
+	}
+
+	number := 1
+	if next.Compare(Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}) == 0 {
+		if current, ok := strings.CutPrefix(v.Pre, pre+"."); ok {
+			if n, err := strconv.Atoi(current); err == nil {
+				number = n + 1
+			}
+		}
+	}
+	next.Pre = fmt.Sprintf("%s.%d", pre, number)
+	// Switching identifiers can sort lower: rc.2 comes after beta.1.
+	if next.Compare(v) <= 0 {
+		return Version{}, fmt.Errorf("%w: %s does not come after %s", InvalidVersionError, next, v)
+	}
+	return next, nil
+}
+
+// reaches reports whether releasing the pre-release v as is already makes
+// the bump from the version before it.
+func reaches(v Version, bump Bump) bool {
+	switch bump {
+	case BumpMajor:
+		return v.Minor == 0 && v.Patch == 0
+	case BumpMinor:
+		return v.Patch == 0
+	}
+	return true
+}
+
+// LatestVersion returns the highest version among tags and the tag it came
+// from. Tags that are not semantic versions are ignored; ok is false when
+// none is.
+func LatestVersion(tags []string) (latest Version, tag string, ok bool) {
+	for _, candidate := range tags {
+		version, err := ParseVersion(candidate)
+		if err != nil {
+			continue
+		}
+		if !ok || version.Compare(latest) > 0 {
+			latest, tag, ok = version, candidate, true
+		}
+	}
+	return latest, tag, ok
+}
+
+// Plan is the next release worked out from the version tags and the
+// unreleased entries.
+type Plan struct {
+	// Current is the latest released version, 0.0.0 when there is none.
+	Current Version
+	// CurrentTag is the tag Current was read from, empty when there is none.
+	CurrentTag string
+	Bump       Bump
+	Next       Version
+	// TagPrefix is what the latest tag puts in front of its version.
+	TagPrefix string
+}
+
+// NextTag is the tag name for the next version.
+func (p Plan) NextTag() string {
+	return p.TagPrefix + p.Next.String()
+}
+
+// PlanNext works out the version following the latest of tags for
+// entries, as a pre-release when pre is set.
+func PlanNext(tags []string, entries []Entry, pre string) (Plan, error) {
+	if len(entries) == 0 {
+		return Plan{}, NoEntriesError
+	}
+	plan := Plan{TagPrefix: DefaultTagPrefix, Bump: BumpFor(entries)}
+	if current, tag, ok := LatestVersion(tags); ok {
+		plan.Current, plan.CurrentTag = current, tag
+		plan.TagPrefix = strings.TrimSuffix(tag, current.String())
+	}
+
+	next, err := plan.Current.Next(plan.Bump, pre)
+	if err != nil {
+		return Plan{}, err
+	}
+	plan.Next = next
+	return plan, nil
+}
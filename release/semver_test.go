@@ -0,0 +1,155 @@
+package release
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abirhasanmubin/changelog-go/changelog"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Version
+		wantErr bool
+	}{
+		{"1.2.3", Version{Major: 1, Minor: 2, Patch: 3}, false},
+		{"v0.10.0-rc.1+build.5", Version{Minor: 10, Pre: "rc.1", Build: "build.5"}, false},
+		{"1.2", Version{}, true},
+		{"nightly", Version{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseVersion(%q) = %+v, %v, want %+v", tt.input, got, err, tt.want)
+		}
+		if err != nil && !errors.Is(err, InvalidVersionError) {
+			t.Errorf("expected InvalidVersionError, got %v", err)
+		}
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	// Ascending precedence, from the semver specification.
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0"}
+
+	for i := 0; i < len(ordered)-1; i++ {
+		lower, _ := ParseVersion(ordered[i])
+		higher, _ := ParseVersion(ordered[i+1])
+		if lower.Compare(higher) != -1 || higher.Compare(lower) != 1 {
+			t.Errorf("expected %s < %s", lower, higher)
+		}
+	}
+	a, _ := ParseVersion("1.0.0+one")
+	b, _ := ParseVersion("1.0.0+two")
+	if a.Compare(b) != 0 {
+		t.Error("expected build metadata to be ignored")
+	}
+}
+
+func TestVersion_Next(t *testing.T) {
+	tests := []struct {
+		current string
+		bump    Bump
+		pre     string
+		want    string
+	}{
+		{"1.3.2", BumpPatch, "", "1.3.3"},
+		{"1.3.2", BumpMinor, "", "1.4.0"},
+		{"1.3.2", BumpMajor, "", "2.0.0"},
+		{"0.0.0", BumpMinor, "", "0.1.0"},
+		{"1.3.2", BumpMinor, "rc", "1.4.0-rc.1"},
+		{"1.4.0-rc.1", BumpMinor, "rc", "1.4.0-rc.2"},
+		{"1.4.0-rc.9", BumpPatch, "rc", "1.4.0-rc.10"},
+		{"1.4.0-beta.2", BumpMinor, "rc", "1.4.0-rc.1"},
+		{"1.4.0-rc.2", BumpMinor, "", "1.4.0"},
+		{"1.4.0-rc.2", BumpMajor, "", "2.0.0"},
+		{"1.4.0-rc.2", BumpMajor, "rc", "2.0.0-rc.1"},
+		{"2.0.0-rc.1", BumpMajor, "", "2.0.0"},
+	}
+
+	for _, tt := range tests {
+		current, _ := ParseVersion(tt.current)
+		got, err := current.Next(tt.bump, tt.pre)
+		if err != nil {
+			t.Errorf("%s.Next(%s, %q): unexpected error: %v", tt.current, tt.bump, tt.pre, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("%s.Next(%s, %q) = %s, want %s", tt.current, tt.bump, tt.pre, got, tt.want)
+		}
+	}
+
+	if _, err := (Version{}).Next(BumpPatch, "rc 1"); !errors.Is(err, InvalidVersionError) {
+		t.Errorf("expected InvalidVersionError for a bad identifier, got %v", err)
+	}
+	rc, _ := ParseVersion("1.4.0-rc.2")
+	if got, err := rc.Next(BumpMinor, "beta"); !errors.Is(err, InvalidVersionError) {
+		t.Errorf("expected InvalidVersionError going from rc to beta, got %s, %v", got, err)
+	}
+}
+
+func entryOfTypes(names ...string) Entry {
+	entry := Entry{}
+	for _, name := range names {
+		entry.Types = append(entry.Types, changelog.ChangeType{Name: name, Selected: true})
+	}
+	return entry
+}
+
+func TestBumpFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []Entry
+		want    Bump
+	}{
+		{"none", nil, BumpNone},
+		{"fix", []Entry{entryOfTypes("Bug fix")}, BumpPatch},
+		{"other types", []Entry{entryOfTypes("Documentation update")}, BumpPatch},
+		{"feature", []Entry{entryOfTypes("Bug fix"), entryOfTypes("New feature")}, BumpMinor},
+		{"breaking", []Entry{entryOfTypes("New feature", "Breaking change"), entryOfTypes("Bug fix")}, BumpMajor},
+	}
+
+	for _, tt := range tests {
+		if got := BumpFor(tt.entries); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestLatestVersion(t *testing.T) {
+	latest, tag, ok := LatestVersion([]string{"v1.2.0", "nightly", "v1.10.0", "v1.10.0-rc.1", "1.9.9"})
+	if !ok || tag != "v1.10.0" || latest.String() != "1.10.0" {
+		t.Errorf("expected v1.10.0, got %s from %q (%v)", latest, tag, ok)
+	}
+	if _, _, ok := LatestVersion([]string{"nightly"}); ok {
+		t.Error("expected no version among non-semver tags")
+	}
+}
+
+func TestPlanNext(t *testing.T) {
+	entries := []Entry{entryOfTypes("New feature")}
+
+	plan, err := PlanNext([]string{"v1.3.2", "v1.3.1"}, entries, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.CurrentTag != "v1.3.2" || plan.Bump != BumpMinor || plan.NextTag() != "v1.4.0" {
+		t.Errorf("unexpected plan %+v", plan)
+	}
+
+	plan, err = PlanNext([]string{"2.0.0"}, entries, "rc")
+	if err != nil || plan.NextTag() != "2.1.0-rc.1" {
+		t.Errorf("expected the tag prefix to follow the latest tag, got %q, %v", plan.NextTag(), err)
+	}
+
+	plan, err = PlanNext(nil, entries, "")
+	if err != nil || plan.NextTag() != "v0.1.0" || plan.CurrentTag != "" {
+		t.Errorf("expected v0.1.0 without tags, got %+v, %v", plan, err)
+	}
+
+	if _, err := PlanNext(nil, nil, ""); !errors.Is(err, NoEntriesError) {
+		t.Errorf("expected NoEntriesError, got %v", err)
+	}
+}
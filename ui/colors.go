@@ -1,7 +1,7 @@
 package ui
 
-// Color constants
-const (
+// Color codes, empty once DisableColors is called.
+var (
 	ColorReset  = "\033[0m"
 	ColorRed    = "\033[31m"
 	ColorGreen  = "\033[32m"
@@ -13,3 +13,15 @@ const (
 	ColorBold   = "\033[1m"
 	ColorDim    = "\033[2m"
 )
+
+// DisableColors blanks every color code so output is plain text.
+func DisableColors() {
+	for _, color := range []*string{&ColorReset, &ColorRed, &ColorGreen, &ColorYellow, &ColorBlue, &ColorPurple, &ColorCyan, &ColorWhite, &ColorBold, &ColorDim} {
+		*color = ""
+	}
+}
+
+// ColorsEnabled reports whether color codes are still emitted.
+func ColorsEnabled() bool {
+	return ColorReset != ""
+}
@@ -0,0 +1,24 @@
+package ui
+
+import "testing"
+
+func TestDisableColors(t *testing.T) {
+	saved := []string{ColorReset, ColorRed, ColorGreen, ColorYellow, ColorBlue, ColorPurple, ColorCyan, ColorWhite, ColorBold, ColorDim}
+	defer func() {
+		ColorReset, ColorRed, ColorGreen, ColorYellow, ColorBlue = saved[0], saved[1], saved[2], saved[3], saved[4]
+		ColorPurple, ColorCyan, ColorWhite, ColorBold, ColorDim = saved[5], saved[6], saved[7], saved[8], saved[9]
+	}()
+
+	if !ColorsEnabled() {
+		t.Fatal("expected colors to start enabled")
+	}
+	DisableColors()
+	if ColorsEnabled() {
+		t.Error("expected colors to be disabled")
+	}
+	for i, color := range []string{ColorReset, ColorRed, ColorGreen, ColorYellow, ColorBlue, ColorPurple, ColorCyan, ColorWhite, ColorBold, ColorDim} {
+		if color != "" {
+			t.Errorf("expected color %d to be blank, got %q", i, color)
+		}
+	}
+}
@@ -20,6 +20,18 @@ func NewMultiSelect(options []string) *MultiSelect {
 	return ms
 }
 
+// Preselect marks options as selected before the selector is shown.
+func (ms *MultiSelect) Preselect(options []string) *MultiSelect {
+	for i, option := range ms.options {
+		for _, selected := range options {
+			if option == selected {
+				ms.selected[i] = true
+			}
+		}
+	}
+	return ms
+}
+
 func (ms *MultiSelect) Run(question string) (map[string]string, error) {
 	fmt.Printf("%s? %s:%s\n", ColorBlue, question, ColorReset)
 	fmt.Printf("%sUse j/k or ↑/↓ to navigate, SPACE to select, 'a' to toggle all, ENTER to confirm%s\n", ColorDim, ColorReset)
@@ -68,3 +68,12 @@ func TestMultiSelectHasSelection(t *testing.T) {
 		t.Error("expected no selection after removing all selections")
 	}
 }
+
+func TestMultiSelect_Preselect(t *testing.T) {
+	ms := NewMultiSelect([]string{"Bug fix", "New feature", "Other"}).Preselect([]string{"Other", "Bug fix", "Unknown"})
+
+	result := ms.getResult()
+	if result["Bug fix"] != "Bug fix" || result["Other"] != "Other" || result["New feature"] != "" {
+		t.Errorf("unexpected preselection %v", result)
+	}
+}
@@ -1,44 +1,27 @@
 package ui
 
 import (
-	"syscall"
-	"unsafe"
+	"errors"
+	"os"
 )
 
-type termios struct {
-	Iflag  uint64
-	Oflag  uint64
-	Cflag  uint64
-	Lflag  uint64
-	Cc     [20]uint8
-	Ispeed uint64
-	Ospeed uint64
-}
-
-func makeRaw() (*termios, error) {
-	var oldState termios
-	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, 0, syscall.TIOCGETA, uintptr(unsafe.Pointer(&oldState)))
-	if errno != 0 {
-		return nil, errno
-	}
-
-	newState := oldState
-	newState.Lflag &^= syscall.ECHO | syscall.ICANON
-	newState.Cc[syscall.VMIN] = 1
-	newState.Cc[syscall.VTIME] = 0
+// Predefined errors
+var (
+	UnsupportedTerminalError = errors.New("raw terminal mode is not supported on this platform")
+)
 
-	_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, 0, syscall.TIOCSETA, uintptr(unsafe.Pointer(&newState)))
-	if errno != 0 {
-		return nil, errno
-	}
+// IsTerminal reports whether f is attached to a terminal.
+func IsTerminal(f *os.File) bool {
+	return isTerminal(f.Fd())
+}
 
-	return &oldState, nil
+// Interactive reports whether both stdin and stdout are terminals, which
+// the raw-mode selectors need.
+func Interactive() bool {
+	return IsTerminal(os.Stdin) && IsTerminal(os.Stdout)
 }
 
-func restore(oldState *termios) error {
-	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, 0, syscall.TIOCSETA, uintptr(unsafe.Pointer(oldState)))
-	if errno != 0 {
-		return errno
-	}
-	return nil
+// stdinFd is the descriptor raw mode is applied to.
+func stdinFd() uintptr {
+	return os.Stdin.Fd()
 }
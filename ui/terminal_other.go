@@ -0,0 +1,17 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package ui
+
+type termios struct{}
+
+func isTerminal(fd uintptr) bool {
+	return false
+}
+
+func makeRaw() (*termios, error) {
+	return nil, UnsupportedTerminalError
+}
+
+func restore(oldState *termios) error {
+	return nil
+}
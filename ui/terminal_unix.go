@@ -0,0 +1,54 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package ui
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+type termios = syscall.Termios
+
+func getTermios(fd uintptr) (*termios, error) {
+	var state termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlReadTermios, uintptr(unsafe.Pointer(&state)))
+	if errno != 0 {
+		return nil, errno
+	}
+	return &state, nil
+}
+
+func setTermios(fd uintptr, state *termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlWriteTermios, uintptr(unsafe.Pointer(state)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func isTerminal(fd uintptr) bool {
+	_, err := getTermios(fd)
+	return err == nil
+}
+
+func makeRaw() (*termios, error) {
+	fd := stdinFd()
+	oldState, err := getTermios(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	newState := *oldState
+	newState.Lflag &^= syscall.ECHO | syscall.ICANON
+	newState.Cc[syscall.VMIN] = 1
+	newState.Cc[syscall.VTIME] = 0
+
+	if err := setTermios(fd, &newState); err != nil {
+		return nil, err
+	}
+	return oldState, nil
+}
+
+func restore(oldState *termios) error {
+	return setTermios(stdinFd(), oldState)
+}
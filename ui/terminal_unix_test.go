@@ -0,0 +1,61 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package ui
+
+import (
+	"os"
+	"testing"
+)
+
+func TestGetTermios_NotATerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	if _, err := getTermios(r.Fd()); err == nil {
+		t.Error("expected an error for a pipe")
+	}
+}
+
+func TestMakeRaw_RestoresState(t *testing.T) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		t.Skip("no controlling terminal")
+	}
+	defer tty.Close()
+
+	stdin := os.Stdin
+	os.Stdin = tty
+	defer func() { os.Stdin = stdin }()
+
+	oldState, err := makeRaw()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := restore(oldState); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	state, err := getTermios(tty.Fd())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state.Lflag != oldState.Lflag {
+		t.Errorf("expected lflag %#x to be restored, got %#x", oldState.Lflag, state.Lflag)
+	}
+}
+
+func TestIsTerminal_Pipe(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	if IsTerminal(r) || IsTerminal(w) {
+		t.Error("expected pipes not to be terminals")
+	}
+}
@@ -8,19 +8,32 @@ import (
 )
 
 func CopyToClipboard(text string) error {
-	var cmd *exec.Cmd
-	
+	program, args, err := clipboardCommand()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(program, args...)
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
+
+// ClipboardProgram returns the external program CopyToClipboard relies on
+// for the current operating system.
+func ClipboardProgram() (string, error) {
+	program, _, err := clipboardCommand()
+	return program, err
+}
+
+func clipboardCommand() (string, []string, error) {
 	switch runtime.GOOS {
 	case "darwin": // macOS
-		cmd = exec.Command("pbcopy")
+		return "pbcopy", nil, nil
 	case "linux":
-		cmd = exec.Command("xclip", "-selection", "clipboard")
+		return "xclip", []string{"-selection", "clipboard"}, nil
 	case "windows":
-		cmd = exec.Command("clip")
+		return "clip", nil, nil
 	default:
-		return fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+		return "", nil, fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
 	}
-	
-	cmd.Stdin = strings.NewReader(text)
-	return cmd.Run()
-}
\ No newline at end of file
+}